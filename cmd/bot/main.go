@@ -2,22 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net"
+	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"telegram-bot-jira/internal/admin"
 	"telegram-bot-jira/internal/config"
 	"telegram-bot-jira/internal/handlers"
 	"telegram-bot-jira/internal/jira"
 	"telegram-bot-jira/internal/logx"
+	"telegram-bot-jira/internal/metrics"
+	"telegram-bot-jira/internal/text"
 	"telegram-bot-jira/internal/tg"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(healthcheck())
+	}
 	cfg := config.Load()
 	logger := logx.New(cfg.LogLevel)
+	text.SetDefaultLang(cfg.DefaultLanguage)
+	if err := text.LoadTemplates(cfg.TemplatesDir); err != nil {
+		panic(err)
+	}
 
 	tgApi, err := tgbotapi.NewBotAPI(cfg.TelegramBotToken)
 	if err != nil {
@@ -37,13 +52,76 @@ func main() {
 	dispatcher.OnCallback = handlers.Callback()
 	dispatcher.OnReplyBotForComment = handlers.ReplyBotForComment()
 	dispatcher.OnMediaGroup = handlers.MediaReplyBotForComment()
+	dispatcher.OnHelp = handlers.Help()
+	dispatcher.OnMyTickets = handlers.MyTickets()
+	dispatcher.OnNotifyPrivate = handlers.NotifyPrivate()
+	dispatcher.OnWatch = handlers.Watch()
+	dispatcher.OnUnwatch = handlers.Unwatch()
+	dispatcher.OnAssign = handlers.Assign()
+	dispatcher.OnTrack = handlers.Track()
+	dispatcher.OnUntrack = handlers.Untrack()
+	dispatcher.OnSearch = handlers.Search()
+	if cfg.InlineSearch {
+		dispatcher.OnInlineQuery = handlers.InlineQuery()
+	}
+	dispatcher.OnWizard = handlers.WizardMessage()
+	dispatcher.OnLinkJira = handlers.LinkJira()
+	dispatcher.OnUnlinkJira = handlers.UnlinkJira()
+	dispatcher.OnBotAdded = handlers.BotAdded()
 
 	b := tg.New(tgApi, logger, cfg, dispatcher, jiraClient)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if cfg.HTTPAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		mux.Handle("/healthz", b.HealthHandler())
+		mux.Handle("/readyz", b.ReadyHandler())
+		if cfg.AdminToken != "" {
+			mux.Handle("/admin/", admin.Handler("/admin/", b, cfg.AdminToken, jiraClient.BrowseURL, logger))
+		}
+		go serveHTTP(ctx, logger, cfg.HTTPAddr, mux)
+	}
+
 	if err := b.Run(ctx); err != nil {
 		logger.Error("bot stopped", slog.Any("err", err))
 	}
 }
+
+// serveHTTP runs the service HTTP server until ctx is cancelled.
+func serveHTTP(ctx context.Context, logger *slog.Logger, addr string, handler http.Handler) {
+	srv := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	logger.Info("http server listening", slog.String("addr", addr))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("http server stopped", slog.Any("err", err))
+	}
+}
+
+// healthcheck probes /readyz of a running bot for the container healthcheck; the image has no curl.
+func healthcheck() int {
+	host, port, err := net.SplitHostPort(os.Getenv("HTTP_ADDR"))
+	if err != nil {
+		return 1
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get("http://" + net.JoinHostPort(host, port) + "/readyz")
+	if err != nil {
+		return 1
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,217 @@
+// Package admin serves a small token-protected web dashboard over the tickets tracked by the bot.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"telegram-bot-jira/internal/store"
+)
+
+// Backend is the part of the bot the dashboard operates on.
+type Backend interface {
+	// Tickets returns a snapshot of all tracked tickets.
+	Tickets() []store.CreatedTicket
+	// ChatTitle returns a human-readable chat name, empty if unknown.
+	ChatTitle(chatID int64) string
+	// Untrack stops tracking the ticket; false if it was not tracked.
+	Untrack(key string) bool
+	// Resync re-reads comments and status of the ticket from Jira.
+	Resync(ctx context.Context, key string) error
+	// ResendLastNotification sends the last Telegram notification of the ticket again.
+	ResendLastNotification(key string) error
+}
+
+const cookieName = "admin_token"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var dashboardTmpl = template.Must(template.New("dashboard.html.tmpl").Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Local().Format("02.01.2006 15:04")
+	},
+}).ParseFS(templateFS, "templates/dashboard.html.tmpl"))
+
+type handler struct {
+	backend Backend
+	token   string
+	jiraURL func(key string) string
+	log     *slog.Logger
+}
+
+// Handler returns the dashboard mounted at prefix (e.g. "/admin/").
+// Requests must carry the token as "Authorization: Bearer <token>" or open prefix?token=<token> once,
+// which stores it in a cookie.
+func Handler(prefix string, backend Backend, token string, jiraURL func(key string) string, log *slog.Logger) http.Handler {
+	h := &handler{backend: backend, token: token, jiraURL: jiraURL, log: log}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", h.dashboard)
+	mux.HandleFunc("/untrack", h.action(func(r *http.Request, key string) error {
+		if !backend.Untrack(key) {
+			return errNotTracked
+		}
+		return nil
+	}))
+	mux.HandleFunc("/resync", h.action(func(r *http.Request, key string) error {
+		return backend.Resync(r.Context(), key)
+	}))
+	mux.HandleFunc("/resend", h.action(func(r *http.Request, key string) error {
+		return backend.ResendLastNotification(key)
+	}))
+	return http.StripPrefix(strings.TrimSuffix(prefix, "/"), h.auth(mux))
+}
+
+type adminError string
+
+func (e adminError) Error() string { return string(e) }
+
+const errNotTracked = adminError("ticket is not tracked")
+
+func (h *handler) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t := r.URL.Query().Get("token"); t != "" && h.validToken(t) {
+			http.SetCookie(w, &http.Cookie{
+				Name: cookieName, Value: t, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode,
+			})
+			q := r.URL.Query()
+			q.Del("token")
+			http.Redirect(w, r, redirectPath(r, q), http.StatusSeeOther)
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if c, err := r.Cookie(cookieName); err == nil && token == "" {
+			token = c.Value
+		}
+		if !h.validToken(token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *handler) validToken(token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// redirectPath rebuilds the original (unstripped) request path with the given query.
+func redirectPath(r *http.Request, q url.Values) string {
+	path := r.URL.Path
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		path = u.Path
+	}
+	if enc := q.Encode(); enc != "" {
+		return path + "?" + enc
+	}
+	return path
+}
+
+type ticketRow struct {
+	store.CreatedTicket
+	ChatTitle string
+	URL       string
+}
+
+type chatOption struct {
+	ID    int64
+	Title string
+}
+
+type actionButton struct {
+	Path, Label, Confirm string
+}
+
+var actions = []actionButton{
+	{Path: "resync", Label: "Resync"},
+	{Path: "resend", Label: "Re-send"},
+	{Path: "untrack", Label: "Untrack", Confirm: "Stop tracking this ticket?"},
+}
+
+type dashboardData struct {
+	Actions  []actionButton
+	Tickets  []ticketRow
+	Chats    []chatOption
+	Statuses []string
+	Chat     string
+	Status   string
+	Total    int
+	Message  string
+	Error    string
+}
+
+func (h *handler) dashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "" {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	data := dashboardData{Actions: actions, Chat: q.Get("chat"), Status: q.Get("status"), Message: q.Get("msg"), Error: q.Get("err")}
+	chatFilter, _ := strconv.ParseInt(data.Chat, 10, 64)
+
+	tickets := h.backend.Tickets()
+	data.Total = len(tickets)
+	chats := map[int64]string{}
+	statuses := map[string]bool{}
+	for _, t := range tickets {
+		if _, ok := chats[t.ChatID]; !ok {
+			chats[t.ChatID] = h.backend.ChatTitle(t.ChatID)
+		}
+		statuses[t.Status] = true
+		if chatFilter != 0 && t.ChatID != chatFilter || data.Status != "" && t.Status != data.Status {
+			continue
+		}
+		data.Tickets = append(data.Tickets, ticketRow{CreatedTicket: t, ChatTitle: chats[t.ChatID], URL: h.jiraURL(t.Key)})
+	}
+	sort.Slice(data.Tickets, func(i, j int) bool { return data.Tickets[i].CreatedAt.After(data.Tickets[j].CreatedAt) })
+	for id, title := range chats {
+		data.Chats = append(data.Chats, chatOption{ID: id, Title: title})
+	}
+	sort.Slice(data.Chats, func(i, j int) bool { return data.Chats[i].ID < data.Chats[j].ID })
+	for s := range statuses {
+		data.Statuses = append(data.Statuses, s)
+	}
+	sort.Strings(data.Statuses)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTmpl.Execute(w, data); err != nil {
+		h.log.Error("admin: render dashboard", "err", err)
+	}
+}
+
+// action wraps a POST action on a ticket and redirects back to the dashboard with the result.
+func (h *handler) action(fn func(r *http.Request, key string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		key := strings.TrimSpace(r.FormValue("key"))
+		q := url.Values{}
+		for _, name := range []string{"chat", "status"} {
+			if v := r.FormValue(name); v != "" {
+				q.Set(name, v)
+			}
+		}
+		if err := fn(r, key); err != nil {
+			h.log.Warn("admin: action failed", "path", r.URL.Path, "key", key, "err", err)
+			q.Set("err", key+": "+err.Error())
+		} else {
+			h.log.Info("admin: action done", "path", r.URL.Path, "key", key)
+			q.Set("msg", key+": "+strings.TrimPrefix(r.URL.Path, "/")+" ok")
+		}
+		http.Redirect(w, r, "./?"+q.Encode(), http.StatusSeeOther)
+	}
+}
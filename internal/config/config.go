@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"log"
 	"os"
 	"strconv"
@@ -22,11 +23,120 @@ type Config struct {
 	JiraProjectKey         string
 	JiraIssueType          string
 	AggregateIssueKey      string
+	TicketStorage          string
+	TicketPropertyJQL      string
 	JiraReopenStatus       string
 	BotPollProcessInterval int
+	CardEditInterval       int
 	HistoryMessagesLimit   int
 	ClosedTicketTTLHours   int
 	ErrorChatID            int
+	DefaultLanguage        string
+	ChatSettingsFile       string
+	TemplatesDir           string
+	DigestSchedule         string
+	DigestTimezone         string
+	DigestPin              bool
+	DigestStaleDays        int
+	SLAFirstResponse       string
+	SLAResolution          string
+	SLAPriorities          string
+	SLAWarnBefore          string
+	SLABusinessHours       string
+	SLABusinessDays        string
+	SLATimezone            string
+	SLAHolidays            string
+	HTTPAddr               string
+	ForumTopicPerTicket    bool
+	AdminToken             string
+	NotifyTransitions      []string
+	NotifyAssignee         bool
+	NotifyMentionCreator   bool
+	JiraUserMap            string
+	JiraLinkIssueKey       string
+	WizardSteps            []string
+	DuplicateCheck         string
+	InlineSearch           bool
+	Chats                  map[int64]ChatSettings
+}
+
+// ChatSettings holds per-chat overrides loaded from CHAT_SETTINGS_FILE.
+type ChatSettings struct {
+	// Language of bot texts in the chat ("ru", "en"). Empty means: user's Telegram language, then default.
+	Language string `json:"language"`
+	// Digest overrides the scheduled daily digest settings.
+	Digest DigestSettings `json:"digest"`
+	// SLA overrides SLA targets for tickets of the chat.
+	SLA SLASettings `json:"sla"`
+	// TopicPerTicket overrides FORUM_TOPIC_PER_TICKET: create a forum topic for each new ticket.
+	TopicPerTicket *bool `json:"topic_per_ticket"`
+	// Notify overrides which ticket changes are announced in the chat.
+	Notify NotifySettings `json:"notify"`
+	// Create sets fields of issues created from the chat.
+	Create CreateSettings `json:"create"`
+	// Wizard configures the guided creation started by /create_issue without arguments.
+	Wizard WizardSettings `json:"wizard"`
+	// DuplicateCheck overrides DUPLICATE_CHECK: "off", "chat" or "project".
+	DuplicateCheck string `json:"duplicate_check"`
+}
+
+// WizardSettings are steps and options of the creation wizard in a chat.
+type WizardSettings struct {
+	// Steps overrides CREATE_WIZARD_STEPS: "summary", "type", "priority", "component" in the order shown.
+	// An empty list disables the wizard in the chat.
+	Steps      []string `json:"steps"`
+	Types      []string `json:"types"`      // issue type names to choose from
+	Priorities []string `json:"priorities"` // priority names to choose from
+	Components []string `json:"components"` // component names to choose from
+}
+
+// CreateSettings are fields of issues created from a chat. String values are Go text/template
+// templates with the chat title, the creator and the command arguments, e.g. "{{.Chat}}",
+// "{{.Creator.Name}}", "{{.Args.customer}}" for "/create_issue ... customer=ACME".
+// Fields that render empty are left out.
+type CreateSettings struct {
+	Summary    string         `json:"summary"`    // summary template, .Text is the command text; default "{{.Text}}"
+	Labels     []string       `json:"labels"`     // added to the "telegram" label
+	Components []string       `json:"components"` // component names
+	Priority   string         `json:"priority"`   // priority name
+	DueDays    int            `json:"due_days"`   // due date in N days
+	Fields     map[string]any `json:"fields"`     // other fields by ID or name, as Jira REST values
+	Reporter   *bool          `json:"reporter"`   // reporter from the creator's linked Jira account, default true
+}
+
+// Empty reports whether the settings add nothing to created issues.
+func (s CreateSettings) Empty() bool {
+	return s.Summary == "" && len(s.Labels) == 0 && len(s.Components) == 0 && s.Priority == "" && s.DueDays == 0 && len(s.Fields) == 0
+}
+
+// NotifySettings are per-chat notification rules; unset fields fall back to NOTIFY_* variables.
+type NotifySettings struct {
+	// Transitions that notify: "To", "From->To", "*" for any status, "ready" for any closing status.
+	// An empty list disables status notifications.
+	Transitions    []string `json:"transitions"`
+	Assignee       *bool    `json:"assignee"`        // notify on assignee changes
+	MentionCreator *bool    `json:"mention_creator"` // mention the ticket creator in notifications
+}
+
+// SLASettings are per-chat SLA targets as Go durations ("4h", "30m").
+type SLASettings struct {
+	SLATargets
+	Priorities map[string]SLATargets `json:"priorities"` // by Jira priority name
+}
+
+// SLATargets are first response and resolution targets in business time.
+type SLATargets struct {
+	FirstResponse string `json:"first_response"`
+	Resolution    string `json:"resolution"`
+}
+
+// DigestSettings configures the scheduled digest of open tickets in a chat.
+type DigestSettings struct {
+	Schedule  string `json:"schedule"`   // cron expression "min hour dom month dow", overrides DIGEST_SCHEDULE
+	Timezone  string `json:"timezone"`   // IANA timezone, overrides DIGEST_TIMEZONE
+	Pin       *bool  `json:"pin"`        // pin the digest message, overrides DIGEST_PIN
+	StaleDays int    `json:"stale_days"` // highlight tickets not updated for N days, overrides DIGEST_STALE_DAYS
+	Disabled  bool   `json:"disabled"`   // no digest in this chat
 }
 
 func Load() Config {
@@ -44,18 +154,89 @@ func Load() Config {
 		JiraProjectKey:         getenv("JIRA_PROJECT_KEY", ""),
 		JiraIssueType:          getenv("JIRA_ISSUE_TYPE", "Task"),
 		AggregateIssueKey:      getenv("AGGREGATE_ISSUE_KEY", ""),
+		TicketStorage:          getenv("TICKET_STORAGE", "aggregate"),
+		TicketPropertyJQL:      getenv("TICKET_PROPERTY_JQL", ""),
 		JiraReopenStatus:       strings.TrimSpace(getenv("JIRA_REOPEN_STATUS", "")),
 		BotPollProcessInterval: atoi(getenv("POLL_INTERVAL_SECONDS", ""), 10),
+		CardEditInterval:       atoi(getenv("CARD_EDIT_INTERVAL_SECONDS", ""), 60),
 		HistoryMessagesLimit:   atoi(getenv("HISTORY_MESSAGES_LIMIT", ""), 10),
 		ClosedTicketTTLHours:   atoi(getenv("CLOSED_TICKET_TTL_HOURS", ""), 7*24),
 		ErrorChatID:            atoi(getenv("ERROR_CHAT_ID", ""), 0),
+		DefaultLanguage:        getenv("DEFAULT_LANGUAGE", "ru"),
+		ChatSettingsFile:       getenv("CHAT_SETTINGS_FILE", ""),
+		TemplatesDir:           getenv("TEMPLATES_DIR", ""),
+		DigestSchedule:         strings.TrimSpace(getenv("DIGEST_SCHEDULE", "")),
+		DigestTimezone:         getenv("DIGEST_TIMEZONE", ""),
+		DigestPin:              atob(getenv("DIGEST_PIN", ""), false),
+		DigestStaleDays:        atoi(getenv("DIGEST_STALE_DAYS", ""), 3),
+		SLAFirstResponse:       getenv("SLA_FIRST_RESPONSE", ""),
+		SLAResolution:          getenv("SLA_RESOLUTION", ""),
+		SLAPriorities:          getenv("SLA_PRIORITIES", ""),
+		SLAWarnBefore:          getenv("SLA_WARN_BEFORE", "30m"),
+		SLABusinessHours:       getenv("SLA_BUSINESS_HOURS", ""),
+		SLABusinessDays:        getenv("SLA_BUSINESS_DAYS", ""),
+		SLATimezone:            getenv("SLA_TIMEZONE", ""),
+		SLAHolidays:            getenv("SLA_HOLIDAYS", ""),
+		HTTPAddr:               getenv("HTTP_ADDR", ""),
+		ForumTopicPerTicket:    atob(getenv("FORUM_TOPIC_PER_TICKET", ""), false),
+		AdminToken:             getenv("ADMIN_TOKEN", ""),
+		NotifyTransitions:      notifyTransitions(),
+		NotifyAssignee:         atob(getenv("NOTIFY_ASSIGNEE", ""), false),
+		NotifyMentionCreator:   atob(getenv("NOTIFY_MENTION_CREATOR", ""), true),
+		JiraUserMap:            getenv("JIRA_USER_MAP", ""),
+		JiraLinkIssueKey:       getenv("JIRA_LINK_ISSUE_KEY", ""),
+		WizardSteps:            splitList(getenv("CREATE_WIZARD_STEPS", "")),
+		DuplicateCheck:         getenv("DUPLICATE_CHECK", "chat"),
+		InlineSearch:           atob(getenv("INLINE_SEARCH", ""), false),
 	}
 	if cfg.TelegramBotToken == "" {
 		log.Fatal("TELEGRAM_TOKEN is required")
 	}
+	chats, err := loadChatSettings(cfg.ChatSettingsFile)
+	if err != nil {
+		log.Fatalf("CHAT_SETTINGS_FILE: %v", err)
+	}
+	cfg.Chats = chats
 	return cfg
 }
 
+// Chat returns settings of the chat, or zero settings if the chat has no overrides.
+func (c Config) Chat(chatID int64) ChatSettings {
+	return c.Chats[chatID]
+}
+
+// TopicPerTicket reports whether a forum chat gets a dedicated topic for each new ticket.
+func (c Config) TopicPerTicket(chatID int64) bool {
+	if v := c.Chat(chatID).TopicPerTicket; v != nil {
+		return *v
+	}
+	return c.ForumTopicPerTicket
+}
+
+// loadChatSettings reads a JSON object keyed by chat ID, e.g. {"-1001234567890": {"language": "en"}}.
+func loadChatSettings(path string) (map[int64]ChatSettings, error) {
+	chats := make(map[int64]ChatSettings)
+	if path == "" {
+		return chats, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]ChatSettings
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for k, v := range raw {
+		id, err := strconv.ParseInt(strings.TrimSpace(k), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		chats[id] = v
+	}
+	return chats, nil
+}
+
 func getenv(k, d string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
@@ -68,3 +249,33 @@ func atoi(s string, d int) int {
 	}
 	return d
 }
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// notifyTransitions reads NOTIFY_TRANSITIONS: "ready" when unset, none when empty or "none".
+func notifyTransitions() []string {
+	v, ok := os.LookupEnv("NOTIFY_TRANSITIONS")
+	if !ok {
+		return []string{"ready"}
+	}
+	if strings.EqualFold(strings.TrimSpace(v), "none") {
+		return nil
+	}
+	return splitList(v)
+}
+
+func atob(s string, d bool) bool {
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	return d
+}
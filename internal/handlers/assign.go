@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+)
+
+// Assign assigns a ticket in Jira to the sender ("/assign KEY") or to another user
+// ("/assign KEY @user"); the assignee needs a linked Jira account.
+func Assign() tg.HandlerFunc {
+	return func(c *tg.Ctx) error {
+		from := c.Upd.Message.From
+		if from == nil {
+			return nil
+		}
+		lang := c.Lang()
+		key := commandTicketKey(c)
+		if key == "" {
+			return c.Tg.SendMessageHTML(text.TextAssignUsage(lang))
+		}
+		if c.TicketStore.Get(key) == nil {
+			return c.Tg.SendMessageHTML(text.TextTicketNotFromBot(lang, key))
+		}
+		if !c.CanAccessTicket(key) {
+			return c.Tg.SendMessageHTML(text.TextTicketNotYours(lang, key))
+		}
+
+		assignee := tg.TelegramUserOf(from)
+		for _, f := range strings.Fields(tg.StripCommandText(c.Upd.Message.Text)) {
+			if strings.HasPrefix(f, "@") && len(f) > 1 {
+				assignee = tg.TelegramUser{Username: strings.TrimPrefix(f, "@")}
+				break
+			}
+		}
+		account := c.JiraAccount(assignee)
+		if account == "" {
+			return c.Tg.SendMessageHTML(text.TextAssigneeNotLinked(lang, assignee))
+		}
+		if err := c.Jira.AssignIssue(c.Std, key, account); err != nil {
+			if errors.Is(err, jira.ErrNotFound) {
+				return c.Tg.SendMessageHTML(text.TextGetStatusNotFound(lang, key))
+			}
+			c.Log.Error("jira assign failed", "key", key, "err", err)
+			return c.Tg.SendMessage(text.TextAssignFailed(lang, key, err))
+		}
+		return c.Tg.SendMessageHTML(text.TextAssigned(lang, key, assignee))
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"fmt"
 	"strings"
 
 	"telegram-bot-jira/internal/text"
@@ -11,8 +10,11 @@ import (
 )
 
 const (
-	actionReopen  = "reopen"
-	actionStatus  = "status"
+	actionReopen = tg.CallbackReopen
+	actionStatus = tg.CallbackStatus
+	actionWatch  = tg.CallbackWatch
+	actionWizard = tg.CallbackWizard
+	actionSearch = tg.CallbackSearch
 )
 
 func Callback() tg.HandlerFunc {
@@ -21,12 +23,19 @@ func Callback() tg.HandlerFunc {
 		if cb == nil {
 			return nil
 		}
-		_ = ctx.Tg.EmptyCallback()
-
 		data := strings.Split(cb.Data, "|")
-		if len(data) == 0 {
-			return nil
+		if data[0] == actionWatch {
+			// Answered with its own notification.
+			return handleWatchCallback(ctx, cb, data)
+		}
+		if data[0] == actionWizard {
+			return handleWizardCallback(ctx, cb, data)
+		}
+		if data[0] == actionSearch {
+			return handleSearchCallback(ctx, cb, data)
 		}
+		_ = ctx.Tg.EmptyCallback()
+
 		switch data[0] {
 		case actionReopen:
 			return handleReopenCallback(ctx, cb, data)
@@ -50,18 +59,21 @@ func handleReopenCallback(ctx *tg.Ctx, cb *tgbotapi.CallbackQuery, parts []strin
 
 	chatTitle := cb.Message.Chat.Title
 	if ctx.TicketStore.Get(issueKey) == nil {
-		return ctx.Tg.SendMessageHTML("⏳ Тикет <code>"+issueKey+"</code> слишком старый, его нельзя переоткрыть. Создайте новый через /create_issue.")
+		return ctx.Tg.SendMessageHTML(text.TextTicketTooOldToReopen(ctx.Lang(), issueKey))
+	}
+	if !ctx.CanAccessTicket(issueKey) {
+		return ctx.Tg.SendMessageHTML(text.TextTicketNotYours(ctx.Lang(), issueKey))
 	}
 
 	if err := ctx.Jira.TransitionIssueToStatus(ctx.Std, issueKey, targetStatus); err != nil {
 		ctx.Log.Error("jira transition failed", "key", issueKey, "status", targetStatus, "err", err)
-		_ = ctx.Tg.SendMessage(fmt.Sprintf("Не удалось переоткрыть тикет %s: %v", issueKey, err))
+		_ = ctx.Tg.SendMessage(text.TextReopenFailed(ctx.Lang(), issueKey, err))
 		return err
 	}
 
-	commentAuthor := text.BuildFullNameUser(cb.From)
-	commentBody := text.TextJiraCommentReopen(commentAuthor, chatTitle)
-	if err := ctx.Jira.AddComment(ctx.Std, issueKey, commentBody); err != nil {
+	commentAuthor := ctx.JiraAuthor(cb.From)
+	commentBody := text.TextJiraCommentReopen(ctx.Lang(), commentAuthor, chatTitle)
+	if err := ctx.AddComment(issueKey, commentBody); err != nil {
 		ctx.Log.Error("jira add comment failed", "key", issueKey, "err", err)
 	}
 
@@ -78,4 +90,4 @@ func handleStatusCallback(ctx *tg.Ctx, cb *tgbotapi.CallbackQuery, parts []strin
 	}
 
 	return processGetIssue(ctx, issueKey)
-}
\ No newline at end of file
+}
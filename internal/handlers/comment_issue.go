@@ -10,9 +10,13 @@ func ReplyBotForComment() tg.HandlerFunc {
 	return func(ctx *tg.Ctx) error {
 		message := ctx.Upd.Message
 		keyInMessageReply := ctx.Params.ProjectKeyRegexp.FindString(message.ReplyToMessage.Text)
+		if keyInMessageReply != "" && !ctx.CanAccessTicket(keyInMessageReply) {
+			return ctx.Tg.SendMessageHTML(text.TextTicketNotYours(ctx.Lang(), keyInMessageReply))
+		}
 		if keyInMessageReply != "" {
-			commentText := text.TextJiraCommentUserFromTelegram(message.Text, message.From, message.Chat.Title, message.ReplyToMessage.Text)
-			commentErr := ctx.Jira.AddComment(ctx.Std, keyInMessageReply, commentText)
+			commentText := text.TextJiraCommentUserFromTelegram(ctx.Lang(), ctx.JiraMentions(message.Text), ctx.JiraAuthor(message.From), message.Chat.Title, message.ReplyToMessage.Text,
+				text.MessageLink(message.Chat, message.MessageID))
+			commentErr := ctx.AddComment(keyInMessageReply, commentText)
 
 			if commentErr != nil {
 				ctx.Log.Error("Failed to add comment", "error", commentErr)
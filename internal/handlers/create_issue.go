@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
+	"maps"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"telegram-bot-jira/internal/common"
+	"telegram-bot-jira/internal/jira"
 	"telegram-bot-jira/internal/text"
 	"telegram-bot-jira/internal/tg"
 
@@ -28,46 +32,184 @@ func extractFilesFromHistory(ctx *tg.Ctx, messages []tgbotapi.Message) ([]common
 	return allFiles, nil
 }
 
+// telegramIconURL is the icon of remote links pointing to Telegram chats.
+const telegramIconURL = "https://telegram.org/favicon.ico"
+
 func CreateIssue() tg.HandlerFunc {
 	return func(ctx *tg.Ctx) error {
-		messagesInHistory := ctx.HistoryMessages.GetMessages(ctx.Upd.Message.Chat.ID)
-		titleIssue := text.TextTitleIssue(ctx.Upd.Message.Chat.Title)
-		descriptionADF := text.TextDescriptionADF(titleIssue, messagesInHistory, "")
-		key, _, err := ctx.Jira.CreateIssue(ctx.Std, titleIssue, descriptionADF)
-		if err != nil {
-			ctx.Tg.SendMessageErrorChat(text.TextErrorCreateTicketDebug(err))
-			return ctx.Tg.SendMessage(text.TextErrorCreateTicket(err))
-		}
-		ctx.Log.Info("Issue created", "key", key)
-
-		payload := strings.TrimSpace(tg.StripCommandText(ctx.Upd.Message.Text))
+		message := ctx.Upd.Message
+		payload := strings.TrimSpace(tg.StripCommandText(message.Text))
 		payload, _ = strings.CutPrefix(payload, "@"+ctx.Tg.SelfUserName())
-		storeUsername := ""
-		if ctx.Upd.Message.From != nil {
-			storeUsername = ctx.Upd.Message.From.UserName
+		// The sender is the creator unless another reporter is given as @username;
+		// key=value arguments go to the chat's issue field templates if they use any.
+		creator := tg.TelegramUserOf(message.From)
+		args := make(map[string]string)
+		var words []string
+		reporterGiven := false
+		takesArgs := ctx.Params.TakesCreateArgs(message.Chat.ID)
+		for _, f := range strings.Fields(payload) {
+			if strings.HasPrefix(f, "@") && len(f) > 1 && !reporterGiven {
+				creator = tg.TelegramUser{Username: strings.TrimPrefix(f, "@")}
+				reporterGiven = true
+				continue
+			}
+			if m := createArgRe.FindStringSubmatch(f); m != nil && takesArgs {
+				args[m[1]] = m[2]
+				continue
+			}
+			words = append(words, f)
 		}
-		storeName := ""
-		if payload != "" {
-			fields := strings.Fields(payload)
-			for i, f := range fields {
-				if strings.HasPrefix(f, "@") && len(f) > 1 {
-					storeUsername = strings.TrimPrefix(f, "@")
-					fields = append(fields[:i], fields[i+1:]...)
-					break
-				}
+		storeName := strings.Join(words, " ")
+
+		// Without a name the chat's wizard asks for the details.
+		if storeName == "" && message.From != nil {
+			if steps := ctx.Params.WizardSteps(message.Chat.ID); len(steps) > 0 {
+				return startWizard(ctx, steps, creator, args)
 			}
-			storeName = strings.TrimSpace(strings.Join(fields, " "))
 		}
+		return createTicket(ctx, newTicket{
+			chat:      message.Chat,
+			messageID: message.MessageID,
+			name:      storeName,
+			creator:   creator,
+			args:      args,
+			history:   ctx.HistoryMessages.GetMessages(message.Chat.ID, ctx.ThreadID),
+		})
+	}
+}
+
+// newTicket is a ticket to create from the chat, by a command or by the wizard.
+type newTicket struct {
+	chat      *tgbotapi.Chat
+	messageID int // the /create_issue message
+	name      string
+	creator   tg.TelegramUser
+	args      map[string]string
+	history   []tgbotapi.Message
+	choices   map[string]string // wizard choices, set over the chat's issue fields
+	force     bool              // create without offering similar tickets
+}
+
+// createTicket creates the Jira issue, stores the ticket and posts its card.
+func createTicket(ctx *tg.Ctx, t newTicket) error {
+	lang := ctx.Lang()
+	titleIssue := text.TextTitleIssue(lang, t.chat.Title)
+	chatURL := text.ChatLink(t.chat, t.messageID)
+	descriptionADF := text.TextDescriptionADF(lang, titleIssue, t.history, chatURL)
+
+	data := tg.IssueFieldsData{
+		Chat:    t.chat.Title,
+		ChatID:  t.chat.ID,
+		Creator: t.creator,
+		Text:    t.name,
+		Args:    t.args,
+	}
+	// The summary is the ticket name, else the first message of the history, else the chat title.
+	summaryData := data
+	if summaryData.Text == "" {
+		summaryData.Text = firstHistoryText(t.history)
+	}
+	// Similar open tickets are offered first: the report may be added to one of them instead.
+	if user := ctx.Tg.CurrentUser(); user != nil && !t.force {
+		if duplicates := ctx.FindDuplicates(t.chat.ID, summaryData.Text); len(duplicates) > 0 {
+			return offerDuplicates(ctx, user.ID, t, duplicates)
+		}
+	}
+
+	fields, err := ctx.IssueFields(data)
+	if err != nil {
+		ctx.Tg.SendMessageErrorChat(text.TextErrorCreateTicketDebug(text.DefaultLang(), err))
+		return ctx.Tg.SendMessage(text.TextErrorCreateTicket(lang, err))
+	}
+	maps.Copy(fields, tg.WizardFields(t.choices))
+	summary, err := ctx.IssueSummary(summaryData)
+	if err != nil {
+		ctx.Tg.SendMessageErrorChat(text.TextErrorCreateTicketDebug(text.DefaultLang(), err))
+		return ctx.Tg.SendMessage(text.TextErrorCreateTicket(lang, err))
+	}
+	if summary == "" {
+		summary = titleIssue
+	}
+	key, err := createJiraIssue(ctx, summary, descriptionADF, fields)
+	if err != nil {
+		ctx.Tg.SendMessageErrorChat(text.TextErrorCreateTicketDebug(text.DefaultLang(), err))
+		return ctx.Tg.SendMessage(text.TextErrorCreateTicket(lang, err))
+	}
+	ctx.Log.Info("Issue created", "key", key)
+	if chatURL != "" {
+		err := ctx.Jira.AddRemoteLink(ctx.Std, key, chatURL, chatURL, text.TextRemoteLinkTitle(lang, t.chat.Title), telegramIconURL)
+		if err != nil {
+			ctx.Log.Warn("Failed to add chat remote link", "key", key, "error", err)
+		}
+	}
+
+	ctx.TicketStore.Add(t.chat.ID, key, "", summary, t.creator)
+	threadID := ctx.ThreadID
+	if ctx.Forum && ctx.Params.TopicPerTicket(t.chat.ID) {
+		threadID = createTicketTopic(ctx, t.chat, key, summary)
+	}
+	ctx.TicketStore.Update(key, func(ticket *tg.CreatedTicket) bool {
+		ticket.Summary = summary
+		ticket.SourceMessageID = t.messageID
+		ticket.ThreadID = threadID
+		return true
+	})
+
+	// The ticket card goes to the ticket's topic, which may be a new one.
+	originThreadID := ctx.ThreadID
+	ctx.ThreadID = threadID
+	errGetIssue := processGetIssue(ctx, key)
+	ctx.ThreadID = originThreadID
+
+	// Extract and attach files from message history
+	AddAttachment(ctx, t.history, key)
 
-		ctx.TicketStore.Add(ctx.Upd.Message.Chat.ID, key, "", storeName, storeUsername)
+	return errGetIssue
+}
 
-		errGetIssue := processGetIssue(ctx, key)
+// firstHistoryText returns the text or caption of the first history message that has one.
+func firstHistoryText(messages []tgbotapi.Message) string {
+	for _, msg := range messages {
+		if s := strings.TrimSpace(msg.Text); s != "" {
+			return s
+		}
+		if s := strings.TrimSpace(msg.Caption); s != "" {
+			return s
+		}
+	}
+	return ""
+}
 
-		// Extract and attach files from message history
-		AddAttachment(ctx, messagesInHistory, key)
+// createArgRe matches a key=value argument of /create_issue.
+var createArgRe = regexp.MustCompile(`^(\w+)=(.+)$`)
 
-		return errGetIssue
+// createJiraIssue creates the issue with the given extra fields. If Jira rejects the reporter
+// (e.g. the bot may not set it), the issue is created without one. Other errors are not retried:
+// after a timeout or a server error the issue may have been created already.
+func createJiraIssue(ctx *tg.Ctx, summary string, description map[string]any, fields map[string]any) (string, error) {
+	key, _, err := ctx.Jira.CreateIssue(ctx.Std, summary, description, fields)
+	if errors.Is(err, jira.ErrReporterRejected) && fields["reporter"] != nil {
+		ctx.Log.Warn("Failed to create issue with reporter, retrying without it", "reporter", fields["reporter"], "error", err)
+		delete(fields, "reporter")
+		key, _, err = ctx.Jira.CreateIssue(ctx.Std, summary, description, fields)
+	}
+	return key, err
+}
+
+// createTicketTopic creates a forum topic for the ticket and posts a link to it into the current topic.
+// Returns the current topic if creation fails.
+func createTicketTopic(ctx *tg.Ctx, chat *tgbotapi.Chat, key, name string) int {
+	lang := ctx.Lang()
+	threadID, err := ctx.Tg.CreateForumTopic(text.TextTicketTopicName(lang, key, name))
+	if err != nil {
+		ctx.Log.Warn("Failed to create forum topic", "key", key, "error", err)
+		return ctx.ThreadID
+	}
+	link := text.MessageLink(chat, threadID)
+	if err := ctx.Tg.SendMessageHTML(text.TextTicketTopicCreatedHTML(lang, key, link)); err != nil {
+		ctx.Log.Warn("Failed to send forum topic link", "key", key, "error", err)
 	}
+	return threadID
 }
 
 func AddAttachment(c *tg.Ctx, messagesInHistory []tgbotapi.Message, key string) {
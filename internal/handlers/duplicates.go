@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"maps"
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// offerDuplicates asks the user to add the report to one of the similar tickets or to create a new one.
+// The pending ticket is kept as a one-step wizard.
+func offerDuplicates(ctx *tg.Ctx, userID int64, t newTicket, duplicates []tg.Duplicate) error {
+	choices := maps.Clone(t.choices)
+	if choices == nil {
+		choices = make(map[string]string)
+	}
+	state := tg.WizardState{
+		Steps:      []string{tg.WizardStepDuplicate},
+		Summary:    t.name,
+		Choices:    choices,
+		Creator:    t.creator,
+		Args:       t.args,
+		History:    t.history,
+		ThreadID:   ctx.ThreadID,
+		MessageID:  t.messageID,
+		Duplicates: duplicates,
+	}
+	return showWizardStep(ctx, userID, state, 0)
+}
+
+// mergeIntoTicket adds the report to an existing ticket of the chat as a comment with the files of the
+// history and subscribes the reporter to the ticket. The key is one of the offered duplicates, which are
+// all tracked by the chat; anything else is refused.
+func mergeIntoTicket(ctx *tg.Ctx, chat *tgbotapi.Chat, state tg.WizardState, key string) error {
+	lang := ctx.Lang()
+	if ticket := ctx.TicketStore.Get(key); ticket == nil || ticket.ChatID != chat.ID {
+		return ctx.Tg.SendMessage(text.TextWizardExpired(lang))
+	}
+	var texts []string
+	if state.Summary != "" {
+		texts = append(texts, state.Summary)
+	}
+	for _, msg := range state.History {
+		if msg.Text != "" {
+			texts = append(texts, msg.Text)
+		} else if msg.Caption != "" {
+			texts = append(texts, msg.Caption)
+		}
+	}
+	files, _ := extractFilesFromHistory(ctx, state.History)
+	body := text.TextJiraCommentUserFromTelegram(lang, ctx.JiraMentions(strings.Join(texts, "\n")), ctx.JiraAuthor(ctx.Tg.CurrentUser()),
+		chat.Title, "", text.MessageLink(chat, state.MessageID))
+	if err := ctx.AddCommentWithFiles(key, body, text.TextJiraAttachment(lang), files); err != nil {
+		ctx.Log.Error("Failed to add report to similar ticket", "key", key, "error", err)
+		return ctx.Tg.SendMessage(text.TextMergeFailed(lang, key, err))
+	}
+	ctx.Log.Info("Report added to similar ticket", "key", key)
+	if _, err := ctx.WatchTicket(key, state.Creator, true); err != nil {
+		ctx.Log.Warn("Failed to subscribe reporter to ticket", "key", key, "error", err)
+	}
+	return ctx.Tg.SendMessageHTML(text.TextMergedHTML(lang, key, ctx.Jira.BrowseURL(key)))
+}
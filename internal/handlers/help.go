@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+)
+
+// Help renders the onboarding guide for /help (and /start in private chats).
+func Help() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		return ctx.Tg.SendMessageHTML(helpText(ctx))
+	}
+}
+
+// BotAdded greets a group the bot has just been added to and posts the guide.
+func BotAdded() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		member := ctx.Upd.MyChatMember
+		if member == nil || member.Chat.IsPrivate() || member.Chat.IsChannel() {
+			return nil
+		}
+		if !isLeftStatus(member.OldChatMember.Status) || isLeftStatus(member.NewChatMember.Status) {
+			return nil
+		}
+		ctx.Log.Info("Bot added to chat", "chat", member.Chat.ID, "title", member.Chat.Title)
+		return ctx.Tg.SendMessageHTML(text.TextBotAddedHTML(ctx.Lang(), member.Chat.Title) + "\n\n" + helpText(ctx))
+	}
+}
+
+func helpText(ctx *tg.Ctx) string {
+	private := false
+	if chat := ctx.Tg.CurrentChat(); chat != nil {
+		private = chat.IsPrivate()
+	}
+
+	lang := ctx.Lang()
+	return text.TextHelpHTML(lang, tg.BotCommands(lang, private), text.HelpInfo{
+		BotUserName:   ctx.Tg.SelfUserName(),
+		ProjectKey:    ctx.Params.ProjectKey,
+		ReopenEnabled: ctx.Params.ReopenStatus != "",
+		ErrorChat:     ctx.Params.HasErrorChat(),
+		Private:       private,
+	})
+}
+
+func isLeftStatus(status string) bool {
+	return status == "left" || status == "kicked" || status == ""
+}
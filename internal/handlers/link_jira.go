@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+)
+
+// LinkJira links the user's Telegram account to a Jira account in a private chat:
+// "/link_jira email" mails a confirmation code through Jira, "/link_jira code" confirms it,
+// without an argument the current link is shown.
+func LinkJira() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		user := ctx.Upd.Message.From
+		if user == nil {
+			return nil
+		}
+		lang := ctx.Lang()
+		arg := strings.TrimSpace(tg.StripCommandText(ctx.Upd.Message.Text))
+		switch {
+		case arg == "":
+			if link, ok := ctx.JiraLink(tg.TelegramUserOf(user)); ok {
+				return ctx.Tg.SendMessageHTML(text.TextLinkStatus(lang, link.Name, link.Email))
+			}
+			return ctx.Tg.SendMessageHTML(text.TextLinkUsage(lang))
+		case strings.Contains(arg, "@"):
+			_, err := ctx.RequestJiraLink(arg)
+			switch {
+			case errors.Is(err, tg.ErrLinkNotConfigured):
+				return ctx.Tg.SendMessageHTML(text.TextLinkNotConfigured(lang))
+			case errors.Is(err, tg.ErrLinkUserNotFound):
+				return ctx.Tg.SendMessageHTML(text.TextLinkUserNotFound(lang))
+			case errors.Is(err, tg.ErrLinkTooSoon):
+				return ctx.Tg.SendMessageHTML(text.TextLinkTooSoon(lang))
+			case err != nil:
+				ctx.Log.Error("jira link request failed", "user", user.ID, "err", err)
+				return ctx.Tg.SendMessageHTML(text.TextLinkFailed(lang, err))
+			}
+			return ctx.Tg.SendMessageHTML(text.TextLinkCodeSent(lang, arg))
+		default:
+			link, ok := ctx.ConfirmJiraLink(arg)
+			if !ok {
+				return ctx.Tg.SendMessageHTML(text.TextLinkBadCode(lang))
+			}
+			return ctx.Tg.SendMessageHTML(text.TextLinkConfirmed(lang, link.Name))
+		}
+	}
+}
+
+// UnlinkJira removes the user's Jira account link.
+func UnlinkJira() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		return ctx.Tg.SendMessage(text.TextUnlink(ctx.Lang(), ctx.UnlinkJira()))
+	}
+}
@@ -114,9 +114,14 @@ func processMediaGroup(ctx *tg.Ctx, group *MediaGroup) {
 			ctx.Log.Error("Failed to find project key in reply text", "replyText", replyText)
 			return
 		}
-		commentErr := ctx.Jira.AddCommentWithEmbeddedFiles(ctx.Std,
-			key,
-			text.TextJiraCommentUserFromTelegram(combinedText, messageWithReplay.From, messageWithReplay.Chat.Title, replyText),
+		if !ctx.CanAccessTicket(key) {
+			_ = ctx.Tg.SendMessageHTML(text.TextTicketNotYours(ctx.Lang(), key))
+			return
+		}
+		commentErr := ctx.AddCommentWithFiles(key,
+			text.TextJiraCommentUserFromTelegram(ctx.Lang(), ctx.JiraMentions(combinedText), ctx.JiraAuthor(messageWithReplay.From), messageWithReplay.Chat.Title, replyText,
+				text.MessageLink(messageWithReplay.Chat, messageWithReplay.MessageID)),
+			text.TextJiraAttachment(ctx.Lang()),
 			allFiles)
 		if commentErr != nil {
 			ctx.Log.Error("Failed to add comment for media group", "error", commentErr)
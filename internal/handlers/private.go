@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxTicketButtons limits the ticket buttons under /my_tickets.
+const maxTicketButtons = 20
+
+// MyTickets lists tickets created by the user across all chats; each button opens the ticket card.
+func MyTickets() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		user := ctx.Upd.Message.From
+		if user == nil {
+			return nil
+		}
+		lang := ctx.Lang()
+		tickets := ctx.TicketStore.ListByCreator(user.ID, user.UserName)
+		sort.Slice(tickets, func(i, j int) bool { return tickets[i].CreatedAt.After(tickets[j].CreatedAt) })
+
+		data := text.MyTicketsData{Total: len(tickets), NotifyPrivate: ctx.NotifiesPrivately(user.ID)}
+		chatIndex := make(map[int64]int)
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, ticket := range tickets {
+			i, ok := chatIndex[ticket.ChatID]
+			if !ok {
+				i = len(data.Chats)
+				chatIndex[ticket.ChatID] = i
+				data.Chats = append(data.Chats, text.MyTicketsChat{Title: ctx.Tg.ChatTitle(ticket.ChatID)})
+			}
+			name := strings.TrimSpace(ticket.Name)
+			if name == "" {
+				name = text.TextTitleIssue(lang, "")
+			}
+			data.Chats[i].Tickets = append(data.Chats[i].Tickets, text.TicketLine{Key: ticket.Key, Name: name, Status: ticket.Status})
+			if len(rows) < maxTicketButtons {
+				label := ticket.Key + " · " + text.GetStatusWithIcon(lang, ticket.Status)
+				rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData(label, tg.CallbackStatus+"|"+ticket.Key),
+				))
+			}
+		}
+		return ctx.Tg.SendMessageHTML(text.TextMyTicketsHTML(lang, data), rows...)
+	}
+}
+
+// NotifyPrivate switches notifications about the user's tickets between the group chats and
+// the private chat: "/notify_private on|off", without an argument toggles. The choice also applies
+// to tickets created later.
+func NotifyPrivate() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		user := ctx.Upd.Message.From
+		if user == nil {
+			return nil
+		}
+		var enable bool
+		switch strings.ToLower(tg.StripCommandText(ctx.Upd.Message.Text)) {
+		case "on":
+			enable = true
+		case "off":
+			enable = false
+		default:
+			enable = !ctx.NotifiesPrivately(user.ID)
+		}
+		ctx.SetNotifyPrivately(user.ID, enable)
+		ctx.Log.Info("Private notifications switched", "user", user.ID, "enabled", enable)
+		return ctx.Tg.SendMessage(text.TextNotifyPrivate(ctx.Lang(), enable))
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// searchPageSize is the number of issues on a /search page.
+	searchPageSize = 5
+	// inlineResultsSize is the number of issues offered in inline mode.
+	inlineResultsSize = 20
+)
+
+// Search finds issues of the project by text, key or JQL: "/search printer", "/search status = Open".
+// Results are paged with Prev/Next buttons. Group chats find their own tickets; a private chat needs
+// a linked Jira account and finds the issues it may browse.
+func Search() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		lang := ctx.Lang()
+		keys, account, ok := ctx.SearchScope()
+		if !ok {
+			return ctx.Tg.SendMessage(text.TextSearchNeedLink(lang))
+		}
+		query := strings.TrimSpace(tg.StripCommandText(ctx.Upd.Message.Text))
+		jql := ctx.Params.SearchJQL(query, keys)
+		if jql == "" {
+			return ctx.Tg.SendMessageHTML(text.TextSearchUsage(lang))
+		}
+		state := tg.SearchState{Query: query, JQL: jql, Account: account, Pages: []tg.SearchCursor{{}}}
+		body, rows, err := searchPage(ctx, &state, 0)
+		if err != nil {
+			ctx.Log.Warn("jira search failed", "jql", jql, "err", err)
+			return ctx.Tg.SendMessage(text.TextSearchFailed(lang, err))
+		}
+		sent, err := ctx.Tg.SendHTML(body, rows...)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			ctx.SetSearch(sent.MessageID, state)
+		}
+		return nil
+	}
+}
+
+// searchPage fetches a page of the search and renders it with pagination buttons. The start of the
+// next page is remembered in the state. Issues the searching account may not browse are left out.
+func searchPage(ctx *tg.Ctx, state *tg.SearchState, page int) (string, [][]tgbotapi.InlineKeyboardButton, error) {
+	issues, next, err := ctx.SearchIssues(state.JQL, state.Account, state.Pages[page], searchPageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	if next != nil && len(state.Pages) == page+1 {
+		state.Pages = append(state.Pages, *next)
+	}
+	lang := ctx.Lang()
+	if len(issues) == 0 && page+1 == len(state.Pages) {
+		return text.TextSearchEmpty(lang, state.Query), nil, nil
+	}
+	lines := make([]text.IssueLine, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, issueLine(ctx, issue))
+	}
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(text.ButtonPrev(lang), fmt.Sprintf("%s|%d", tg.CallbackSearch, page-1)))
+	}
+	if page+1 < len(state.Pages) {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(text.ButtonNext(lang), fmt.Sprintf("%s|%d", tg.CallbackSearch, page+1)))
+	}
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	return text.TextSearchResultsHTML(lang, state.Query, page+1, lines), rows, nil
+}
+
+// handleSearchCallback shows another page of the search results in place.
+func handleSearchCallback(ctx *tg.Ctx, cb *tgbotapi.CallbackQuery, parts []string) error {
+	if len(parts) < 2 || cb.Message == nil {
+		return ctx.Tg.EmptyCallback()
+	}
+	page, err := strconv.Atoi(parts[1])
+	state, ok := ctx.Search(cb.Message.MessageID)
+	if err != nil || !ok || page < 0 || page >= len(state.Pages) {
+		return ctx.Tg.AnswerCallback(text.TextSearchExpired(ctx.Lang()))
+	}
+	_ = ctx.Tg.EmptyCallback()
+	body, rows, err := searchPage(ctx, &state, page)
+	if err != nil {
+		ctx.Log.Warn("jira search failed", "jql", state.JQL, "err", err)
+		return ctx.Tg.SendMessage(text.TextSearchFailed(ctx.Lang(), err))
+	}
+	ctx.SetSearch(cb.Message.MessageID, state)
+	return ctx.Tg.EditMessageHTML(cb.Message.MessageID, body, rows...)
+}
+
+// InlineQuery answers "@bot KEY-123" or "@bot text" typed in any chat with issue cards to send.
+// Only users with a linked Jira account get results, limited to the issues the account may browse.
+func InlineQuery() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		_, account, ok := ctx.SearchScope()
+		if !ok {
+			return ctx.Tg.AnswerInlineQuery(nil)
+		}
+		jql := ctx.Params.SearchJQL(ctx.Upd.InlineQuery.Query, nil)
+		if jql == "" {
+			return ctx.Tg.AnswerInlineQuery(nil)
+		}
+		issues, _, err := ctx.SearchIssues(jql, account, tg.SearchCursor{}, inlineResultsSize)
+		if err != nil {
+			ctx.Log.Warn("jira inline search failed", "jql", jql, "err", err)
+			return ctx.Tg.AnswerInlineQuery(nil)
+		}
+		lang := ctx.Lang()
+		results := make([]any, 0, len(issues))
+		for _, issue := range issues {
+			line := issueLine(ctx, issue)
+			article := tgbotapi.NewInlineQueryResultArticleHTML(issue.Key, issue.Key+" · "+issue.Summary, text.TextIssueCardHTML(lang, line))
+			article.Description = text.TextInlineDescription(lang, line)
+			article.URL = line.URL
+			results = append(results, article)
+		}
+		return ctx.Tg.AnswerInlineQuery(results)
+	}
+}
+
+func issueLine(ctx *tg.Ctx, issue jira.IssueStatus) text.IssueLine {
+	return text.IssueLine{
+		Key:      issue.Key,
+		Summary:  issue.Summary,
+		Status:   issue.Status,
+		Assignee: strings.TrimSpace(issue.Assignee),
+		Priority: issue.Priority,
+		URL:      ctx.Jira.BrowseURL(issue.Key),
+	}
+}
@@ -2,36 +2,21 @@ package handlers
 
 import (
 	"errors"
-	"fmt"
 	"regexp"
 	"strings"
 
 	"telegram-bot-jira/internal/jira"
 	"telegram-bot-jira/internal/text"
 	"telegram-bot-jira/internal/tg"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func GetIssue() tg.HandlerFunc {
 	return func(c *tg.Ctx) error {
-		project := c.Jira.ProjectKey()
-		re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(project) + `-\d+\b`)
-
-		raw := tg.StripCommandText(c.Upd.Message.Text)
-
-		var key string
-		if m := re.FindString(raw); m != "" {
-			key = strings.ToUpper(m)
-		}
-
-		if key == "" && c.Upd.Message.ReplyToMessage != nil {
-			if m := re.FindString(c.Upd.Message.ReplyToMessage.Text); m != "" {
-				key = strings.ToUpper(m)
-			}
-		}
-
+		key := commandTicketKey(c)
 		if key == "" {
+			if c.IsPrivate() {
+				return MyTickets()(c)
+			}
 			return sendChatTicketsDigest(c)
 		}
 
@@ -39,37 +24,64 @@ func GetIssue() tg.HandlerFunc {
 	}
 }
 
+// commandTicketKey finds a ticket key in the command arguments or, failing that, in the replied message.
+func commandTicketKey(c *tg.Ctx) string {
+	re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(c.Jira.ProjectKey()) + `-\d+\b`)
+	if m := re.FindString(tg.StripCommandText(c.Upd.Message.Text)); m != "" {
+		return strings.ToUpper(m)
+	}
+	if c.Upd.Message.ReplyToMessage != nil {
+		if m := re.FindString(c.Upd.Message.ReplyToMessage.Text); m != "" {
+			return strings.ToUpper(m)
+		}
+	}
+	return ""
+}
+
 func processGetIssue(c *tg.Ctx, key string) error {
 	ticket := c.TicketStore.Get(key)
 	if ticket == nil {
-		return c.Tg.SendMessageHTML("⚠️ Тикет <code>" + key + "</code> был создан не в этом боте")
+		return c.Tg.SendMessageHTML(text.TextTicketNotFromBot(c.Lang(), key))
+	}
+	if !c.CanAccessTicket(key) {
+		return c.Tg.SendMessageHTML(text.TextTicketNotYours(c.Lang(), key))
 	}
 
 	info, err := c.Jira.GetIssueStatus(c.Std, key)
 	if err != nil {
 		if errors.Is(err, jira.ErrNotFound) {
-			return c.Tg.SendMessageHTML(text.TextGetStatusNotFound(key))
+			return c.Tg.SendMessageHTML(text.TextGetStatusNotFound(c.Lang(), key))
 		}
-		return c.Tg.SendMessage(fmt.Sprintf("Не удалось получить информацию по тикету %s: %v", key, err))
+		return c.Tg.SendMessage(text.TextGetStatusFailed(c.Lang(), key, err))
 	}
 
-	var rows [][]tgbotapi.InlineKeyboardButton
-	if c.Params.ReopenStatus != "" && text.IsReadyStatus(info.Status) {
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Переоткрыть", fmt.Sprintf("%s|%s", actionReopen, info.Key)),
-		))
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Обновить статус", fmt.Sprintf("%s|%s", actionStatus, info.Key)),
-		))
-	}
+	rows := tg.TicketKeyboard(c.Lang(), info.Key, info.Status, c.Params.ReopenStatus)
 
-	c.TicketStore.UpdateStatus(info.Key, info.Status)
-	return c.Tg.SendMessageHTML(text.TextGetStatus(info, ticket.Name, ticket.CreatorUsername), rows...)
+	// Later status changes are left to the poller, which notifies about them.
+	if ticket.Status == "" {
+		c.TicketStore.UpdateStatus(info.Key, info.Status)
+	}
+	sent, err := c.Tg.SendHTML(text.TextGetStatus(c.Lang(), info, ticket.Name, ticket.Creator, c.TicketSLA(ticket, info.Priority)), rows...)
+	if err != nil {
+		return err
+	}
+	// The first card in the ticket's own chat topic becomes the message notifications reply to.
+	if ticket.MessageID == 0 && sent.Chat != nil && sent.Chat.ID == ticket.ChatID && c.ThreadID == ticket.ThreadID {
+		c.TicketStore.Update(ticket.Key, func(t *tg.CreatedTicket) bool {
+			t.MessageID = sent.MessageID
+			return true
+		})
+	}
+	return nil
 }
 
 func sendChatTicketsDigest(c *tg.Ctx) error {
 	chatID := c.Upd.Message.Chat.ID
+	// In a forum topic only its tickets are listed, unless every ticket has its own topic.
 	tickets := c.TicketStore.ListByChatID(chatID)
+	if c.ThreadID != 0 && !c.Params.TopicPerTicket(chatID) {
+		tickets = c.TicketStore.ListByThread(chatID, c.ThreadID)
+	}
 
-	return c.Tg.SendMessageHTML(text.TextTelegramTicketsMessage(tickets, c.Upd.Message.Chat.Title))
+	return c.Tg.SendMessageHTML(text.TextTelegramTicketsMessage(c.Lang(), tickets, c.Upd.Message.Chat.Title))
 }
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+)
+
+// anyIssueKeyRe matches an issue key of any Jira project.
+var anyIssueKeyRe = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-\d+\b`)
+
+// Track attaches an existing issue of the project to the chat: "/track KEY". The sender needs a linked
+// Jira account that may browse the issue and follows the ticket.
+func Track() tg.HandlerFunc {
+	return func(c *tg.Ctx) error {
+		from := c.Upd.Message.From
+		if from == nil {
+			return nil
+		}
+		lang := c.Lang()
+		key := commandTicketKey(c)
+		if key == "" {
+			if other := anyIssueKeyRe.FindString(strings.ToUpper(tg.StripCommandText(c.Upd.Message.Text))); other != "" {
+				return c.Tg.SendMessageHTML(text.TextTrackOtherProject(lang, other, c.Params.ProjectKey))
+			}
+			return c.Tg.SendMessageHTML(text.TextTrackUsage(lang))
+		}
+		if ticket := c.TicketStore.Get(key); ticket != nil {
+			return c.Tg.SendMessageHTML(text.TextTrackAlready(lang, key, ticket.ChatID == c.Upd.Message.Chat.ID))
+		}
+		user := tg.TelegramUserOf(from)
+		account := c.JiraAccount(user)
+		if account == "" {
+			return c.Tg.SendMessageHTML(text.TextTrackNotLinked(lang))
+		}
+
+		issue, err := c.Jira.GetIssueStatus(c.Std, key)
+		if errors.Is(err, jira.ErrNotFound) {
+			return c.Tg.SendMessageHTML(text.TextGetStatusNotFound(lang, key))
+		}
+		if err != nil {
+			return c.Tg.SendMessage(text.TextGetStatusFailed(lang, key, err))
+		}
+		allowed, err := c.Jira.HasIssuePermission(c.Std, key, account, "BROWSE_PROJECTS")
+		if err != nil {
+			c.Log.Error("jira permission check failed", "key", key, "account", account, "err", err)
+			return c.Tg.SendMessage(text.TextTrackFailed(lang, key, err))
+		}
+		if !allowed {
+			return c.Tg.SendMessageHTML(text.TextTrackForbidden(lang, key))
+		}
+
+		c.TrackTicket(issue)
+		c.Log.Info("Issue tracked", "key", key, "chat", c.Upd.Message.Chat.ID)
+		if _, err := c.WatchTicket(key, user, true); err != nil {
+			c.Log.Warn("Failed to subscribe to tracked ticket", "key", key, "err", err)
+		}
+		if err := c.Tg.SendMessageHTML(text.TextTracked(lang, key)); err != nil {
+			return err
+		}
+		return processGetIssue(c, key)
+	}
+}
+
+// Untrack detaches an issue attached with /track from the chat: "/untrack KEY". Followers of the ticket
+// stop watching the issue in Jira too. Tickets created by the bot stay until they are closed.
+func Untrack() tg.HandlerFunc {
+	return func(c *tg.Ctx) error {
+		lang := c.Lang()
+		key := commandTicketKey(c)
+		if key == "" {
+			return c.Tg.SendMessageHTML(text.TextUntrackUsage(lang))
+		}
+		ticket := c.TicketStore.Get(key)
+		if ticket == nil || ticket.ChatID != c.Upd.Message.Chat.ID || !ticket.Tracked {
+			return c.Tg.SendMessageHTML(text.TextNotTracked(lang, key))
+		}
+		for _, watcher := range ticket.Watchers {
+			if _, err := c.WatchTicket(key, watcher, false); err != nil {
+				c.Log.Warn("Failed to remove Jira watcher of untracked ticket", "key", key, "user", watcher.Username, "err", err)
+			}
+		}
+		c.TicketStore.Delete(key)
+		c.Log.Info("Issue untracked", "key", key, "chat", c.Upd.Message.Chat.ID)
+		return c.Tg.SendMessageHTML(text.TextUntracked(lang, key))
+	}
+}
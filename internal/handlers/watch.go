@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Watch subscribes the user to a ticket: "/watch KEY" or the command in reply to a ticket message.
+// Watchers are mentioned on new comments and status changes.
+func Watch() tg.HandlerFunc {
+	return watchCommand(true)
+}
+
+// Unwatch removes the user's subscription to a ticket.
+func Unwatch() tg.HandlerFunc {
+	return watchCommand(false)
+}
+
+func watchCommand(watch bool) tg.HandlerFunc {
+	return func(c *tg.Ctx) error {
+		user := c.Upd.Message.From
+		if user == nil {
+			return nil
+		}
+		lang := c.Lang()
+		key := commandTicketKey(c)
+		if key == "" {
+			return c.Tg.SendMessageHTML(text.TextWatchUsage(lang))
+		}
+		if c.TicketStore.Get(key) == nil {
+			return c.Tg.SendMessageHTML(text.TextTicketNotFromBot(lang, key))
+		}
+		if watch && !c.CanAccessTicket(key) {
+			return c.Tg.SendMessageHTML(text.TextTicketNotYours(lang, key))
+		}
+		if _, err := c.WatchTicket(key, tg.TelegramUserOf(user), watch); err != nil {
+			c.Log.Error("watch ticket failed", "key", key, "watch", watch, "err", err)
+			return c.Tg.SendMessage(text.TextWatchFailed(lang, key, err))
+		}
+		return c.Tg.SendMessageHTML(text.TextWatchState(lang, key, watch))
+	}
+}
+
+// handleWatchCallback toggles the subscription from the Follow button and reports it in a callback notification.
+func handleWatchCallback(ctx *tg.Ctx, cb *tgbotapi.CallbackQuery, parts []string) error {
+	if len(parts) < 2 || cb.From == nil {
+		return ctx.Tg.EmptyCallback()
+	}
+	issueKey := strings.TrimSpace(parts[1])
+	ticket := ctx.TicketStore.Get(issueKey)
+	if ticket == nil {
+		return ctx.Tg.EmptyCallback()
+	}
+	watch := !ticket.WatchedBy(cb.From.ID, cb.From.UserName)
+	if watch && !ctx.CanAccessTicket(issueKey) {
+		return ctx.Tg.AnswerCallback(text.TextTicketNotYours(ctx.Lang(), issueKey))
+	}
+	if _, err := ctx.WatchTicket(issueKey, tg.TelegramUserOf(cb.From), watch); err != nil {
+		ctx.Log.Error("watch ticket failed", "key", issueKey, "watch", watch, "err", err)
+		return ctx.Tg.AnswerCallback(text.TextWatchFailed(ctx.Lang(), issueKey, err))
+	}
+	return ctx.Tg.AnswerCallback(text.TextWatchToast(ctx.Lang(), issueKey, watch))
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+	"telegram-bot-jira/internal/tg"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Commands of wizard buttons.
+const (
+	wizardPick   = "pick"
+	wizardBack   = "back"
+	wizardCancel = "cancel"
+	wizardCreate = "create"
+	wizardMerge  = "merge"
+)
+
+// startWizard starts guided creation for /create_issue without a name.
+func startWizard(ctx *tg.Ctx, steps []string, creator tg.TelegramUser, args map[string]string) error {
+	message := ctx.Upd.Message
+	state := tg.WizardState{
+		Steps:     append(slices.Clone(steps), tg.WizardStepConfirm),
+		Choices:   make(map[string]string),
+		Creator:   creator,
+		Args:      args,
+		History:   ctx.HistoryMessages.GetMessages(message.Chat.ID, ctx.ThreadID),
+		ThreadID:  ctx.ThreadID,
+		MessageID: message.MessageID,
+	}
+	return showWizardStep(ctx, message.From.ID, state, 0)
+}
+
+// showWizardStep asks the current step. Choices and the confirmation edit the previous prompt if there
+// is one (promptID); the summary is asked in a new message the user replies to.
+func showWizardStep(ctx *tg.Ctx, userID int64, state tg.WizardState, promptID int) error {
+	lang := ctx.Lang()
+	step := state.Current()
+	n, total := state.Step+1, len(state.Steps)-1
+	if step == tg.WizardStepSummary {
+		if promptID != 0 {
+			_ = ctx.Tg.DeleteMessage(promptID)
+		}
+		sent, err := ctx.Tg.SendForceReply(text.TextWizardStep(lang, step, n, total), state.MessageID, text.TextWizardPlaceholder(lang))
+		if err != nil {
+			return err
+		}
+		state.PromptID = sent.MessageID
+		ctx.SetWizard(userID, state)
+		return nil
+	}
+
+	data := func(command, option string) string {
+		return fmt.Sprintf("%s|%d|%s|%s", tg.CallbackWizard, userID, command, option)
+	}
+	body := wizardPromptText(ctx, state)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	switch step {
+	case tg.WizardStepConfirm:
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonCreate(lang), data(wizardCreate, ""))))
+	case tg.WizardStepDuplicate:
+		for _, d := range state.Duplicates {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonMergeInto(lang, d.Key), data(wizardMerge, d.Key))))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonCreateAnyway(lang), data(wizardCreate, ""))))
+	default:
+		for i, option := range ctx.Params.WizardOptions(ctx.Tg.CurrentChatId(), step) {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(option, data(wizardPick, strconv.Itoa(i)))))
+		}
+	}
+	var nav []tgbotapi.InlineKeyboardButton
+	if state.Step > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(text.ButtonBack(lang), data(wizardBack, "")))
+	}
+	nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(text.ButtonCancel(lang), data(wizardCancel, "")))
+	rows = append(rows, nav)
+
+	if promptID != 0 {
+		if err := ctx.Tg.EditMessageHTML(promptID, body, rows...); err != nil {
+			return err
+		}
+		state.PromptID = promptID
+	} else {
+		sent, err := ctx.Tg.SendHTML(body, rows...)
+		if err != nil {
+			return err
+		}
+		state.PromptID = sent.MessageID
+	}
+	ctx.SetWizard(userID, state)
+	return nil
+}
+
+// wizardPromptText returns the text of a step asked with buttons.
+func wizardPromptText(ctx *tg.Ctx, state tg.WizardState) string {
+	lang := ctx.Lang()
+	switch step := state.Current(); step {
+	case tg.WizardStepConfirm:
+		return text.TextWizardConfirmHTML(lang, text.WizardConfirmData{
+			Summary:   state.Summary,
+			Type:      state.Choices[tg.WizardStepType],
+			Priority:  state.Choices[tg.WizardStepPriority],
+			Component: state.Choices[tg.WizardStepComponent],
+		})
+	case tg.WizardStepDuplicate:
+		lines := make([]text.IssueLine, 0, len(state.Duplicates))
+		for _, d := range state.Duplicates {
+			lines = append(lines, text.IssueLine{Key: d.Key, Summary: d.Summary, Status: d.Status, URL: ctx.Jira.BrowseURL(d.Key)})
+		}
+		return text.TextDuplicatesHTML(lang, lines)
+	default:
+		return text.TextWizardStep(lang, step, state.Step+1, len(state.Steps)-1)
+	}
+}
+
+// WizardMessage handles the summary answered in reply to the wizard prompt and /cancel. Text replies
+// to prompts with buttons are ignored.
+func WizardMessage() tg.HandlerFunc {
+	return func(ctx *tg.Ctx) error {
+		message := ctx.Upd.Message
+		if message.From == nil {
+			return nil
+		}
+		state, ok := ctx.Wizard(message.From.ID)
+		if !ok {
+			return nil
+		}
+		if tg.IsCommand(message.Text, "cancel") {
+			ctx.EndWizard(message.From.ID)
+			_ = ctx.Tg.DeleteMessage(state.PromptID)
+			return ctx.Tg.SendMessage(text.TextWizardCancelled(ctx.Lang()))
+		}
+		if state.Current() != tg.WizardStepSummary {
+			return nil
+		}
+		summary := strings.Join(strings.Fields(message.Text), " ")
+		if summary == "" {
+			// Ask again, e.g. after a sticker.
+			return showWizardStep(ctx, message.From.ID, state, state.PromptID)
+		}
+		state.Summary = summary
+		state.Step++
+		return showWizardStep(ctx, message.From.ID, state, 0)
+	}
+}
+
+// closeWizardPrompt leaves the prompt of a finished wizard without buttons.
+func closeWizardPrompt(ctx *tg.Ctx, state tg.WizardState, promptID int) {
+	if err := ctx.Tg.EditMessageHTML(promptID, wizardPromptText(ctx, state)); err != nil {
+		ctx.Log.Warn("Failed to close wizard prompt", "error", err)
+	}
+}
+
+// handleWizardCallback handles wizard buttons, "wizard|<user ID>|<command>|<option>". Only the user
+// who started the wizard may press them.
+func handleWizardCallback(ctx *tg.Ctx, cb *tgbotapi.CallbackQuery, parts []string) error {
+	if len(parts) < 4 || cb.From == nil || cb.Message == nil {
+		return ctx.Tg.EmptyCallback()
+	}
+	lang := ctx.Lang()
+	userID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return ctx.Tg.EmptyCallback()
+	}
+	if cb.From.ID != userID {
+		return ctx.Tg.AnswerCallback(text.TextWizardNotYours(lang))
+	}
+	state, ok := ctx.Wizard(userID)
+	if !ok || state.PromptID != cb.Message.MessageID {
+		return ctx.Tg.AnswerCallback(text.TextWizardExpired(lang))
+	}
+	_ = ctx.Tg.EmptyCallback()
+
+	switch parts[2] {
+	case wizardPick:
+		options := ctx.Params.WizardOptions(cb.Message.Chat.ID, state.Current())
+		i, err := strconv.Atoi(parts[3])
+		if err != nil || i < 0 || i >= len(options) {
+			return nil
+		}
+		state.Choices[state.Current()] = options[i]
+		state.Step++
+	case wizardBack:
+		if state.Step > 0 {
+			state.Step--
+		}
+	case wizardCancel:
+		ctx.EndWizard(userID)
+		return ctx.Tg.EditMessageHTML(cb.Message.MessageID, text.TextWizardCancelled(lang))
+	case wizardCreate:
+		// Taken under one lock: a double tap must not create two issues.
+		if state, ok = ctx.TakeWizard(userID, cb.Message.MessageID); !ok {
+			return nil
+		}
+		closeWizardPrompt(ctx, state, cb.Message.MessageID)
+		return createTicket(ctx, newTicket{
+			chat:      cb.Message.Chat,
+			messageID: state.MessageID,
+			name:      state.Summary,
+			creator:   state.Creator,
+			args:      state.Args,
+			history:   state.History,
+			choices:   state.Choices,
+			force:     state.Current() == tg.WizardStepDuplicate,
+		})
+	case wizardMerge:
+		key := parts[3]
+		if !slices.ContainsFunc(state.Duplicates, func(d tg.Duplicate) bool { return d.Key == key }) {
+			return nil
+		}
+		if state, ok = ctx.TakeWizard(userID, cb.Message.MessageID); !ok {
+			return nil
+		}
+		closeWizardPrompt(ctx, state, cb.Message.MessageID)
+		return mergeIntoTicket(ctx, cb.Message.Chat, state, key)
+	default:
+		return nil
+	}
+	return showWizardStep(ctx, userID, state, cb.Message.MessageID)
+}
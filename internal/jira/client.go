@@ -10,10 +10,14 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	neturl "net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"telegram-bot-jira/internal/common"
 	"telegram-bot-jira/internal/config"
+	"telegram-bot-jira/internal/metrics"
 	"time"
 )
 
@@ -44,7 +48,7 @@ func New(cfg config.Config) (*Client, error) {
 		projectKey: cfg.JiraProjectKey,
 		issueType:  cfg.JiraIssueType,
 		authHeader: "Basic " + creds,
-		http:       &http.Client{Timeout: 15 * time.Second},
+		http:       &http.Client{Timeout: 15 * time.Second, Transport: instrumentedTransport{next: http.DefaultTransport}},
 	}, nil
 }
 
@@ -64,8 +68,9 @@ type createIssueResponse struct {
 	Self string `json:"self"`
 }
 
-// CreateIssue creates a Jira issue using REST v3 API.
-func (c *Client) CreateIssue(ctx context.Context, summary string, description any) (string, string, error) {
+// CreateIssue creates a Jira issue using REST v3 API. extra fields (e.g. reporter) are added to
+// or override the default ones.
+func (c *Client) CreateIssue(ctx context.Context, summary string, description any, extra map[string]any) (string, string, error) {
 	if strings.TrimSpace(summary) == "" {
 		return "", "", errors.New("jira: summary is required")
 	}
@@ -122,6 +127,9 @@ func (c *Client) CreateIssue(ctx context.Context, summary string, description an
 			}
 		}
 	}
+	for name, value := range extra {
+		fields[name] = value
+	}
 	body, _ := json.Marshal(createIssueRequest{Fields: fields})
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/issue", strings.NewReader(string(body)))
@@ -138,6 +146,9 @@ func (c *Client) CreateIssue(ctx context.Context, summary string, description an
 	defer resp.Body.Close()
 
 	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest && rejectsField(data, "reporter") {
+		return "", "", fmt.Errorf("%w (%d): %s", ErrReporterRejected, resp.StatusCode, truncate(string(data), 512))
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return "", "", fmt.Errorf("jira: create failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
 	}
@@ -148,6 +159,83 @@ func (c *Client) CreateIssue(ctx context.Context, summary string, description an
 	return out.Key, c.baseURL + "/browse/" + out.Key, nil
 }
 
+// FieldMeta describes a field of the create issue screen.
+type FieldMeta struct {
+	FieldID       string         `json:"fieldId"`
+	Name          string         `json:"name"`
+	Required      bool           `json:"required"`
+	HasDefault    bool           `json:"hasDefaultValue"`
+	AllowedValues []AllowedValue `json:"allowedValues"`
+}
+
+// AllowedValue is an option of a field with a fixed set of values (priority, component, select list).
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CreateMeta returns the fields that can be set when creating issues of the configured project and issue type.
+func (c *Client) CreateMeta(ctx context.Context) ([]FieldMeta, error) {
+	issueTypeID := c.issueType
+	if _, err := strconv.Atoi(issueTypeID); err != nil {
+		var types struct {
+			IssueTypes []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"issueTypes"`
+		}
+		if err := c.getCreateMeta(ctx, "/issuetypes?maxResults=200", &types); err != nil {
+			return nil, err
+		}
+		issueTypeID = ""
+		for _, t := range types.IssueTypes {
+			if strings.EqualFold(t.Name, c.issueType) {
+				issueTypeID = t.ID
+			}
+		}
+		if issueTypeID == "" {
+			return nil, fmt.Errorf("jira: issue type %q not found in project %s", c.issueType, c.projectKey)
+		}
+	}
+	var fields []FieldMeta
+	for startAt := 0; ; {
+		var page struct {
+			Fields []FieldMeta `json:"fields"`
+			Total  int         `json:"total"`
+		}
+		if err := c.getCreateMeta(ctx, fmt.Sprintf("/issuetypes/%s?maxResults=200&startAt=%d", issueTypeID, startAt), &page); err != nil {
+			return nil, err
+		}
+		fields = append(fields, page.Fields...)
+		startAt += len(page.Fields)
+		if len(page.Fields) == 0 || startAt >= page.Total {
+			return fields, nil
+		}
+	}
+}
+
+func (c *Client) getCreateMeta(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/issue/createmeta/"+c.projectKey+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jira: get create meta failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return json.Unmarshal(data, out)
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -155,18 +243,196 @@ func truncate(s string, n int) string {
 	return s[:n] + "…"
 }
 
+// User is a Jira user account.
+type User struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"emailAddress"`
+}
+
+// Myself returns the account the client is authenticated as.
+func (c *Client) Myself(ctx context.Context) (*User, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/myself", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira: get myself failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var out User
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FindUsers searches Jira users by email, name or username.
+func (c *Client) FindUsers(ctx context.Context, query string) ([]User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("jira: user query is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/user/search?query="+neturl.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira: find users failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var out []User
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotifyUsers emails a notification about the issue to the given accounts only.
+func (c *Client) NotifyUsers(ctx context.Context, key, subject, body string, accountIDs []string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || len(accountIDs) == 0 {
+		return errors.New("jira: issue key and recipients are required")
+	}
+	users := make([]map[string]string, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		users = append(users, map[string]string{"accountId": id})
+	}
+	payload, _ := json.Marshal(map[string]any{
+		"subject":  subject,
+		"textBody": body,
+		"to":       map[string]any{"users": users},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/issue/"+key+"/notify", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: notify failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// AssignIssue sets the issue assignee; an empty accountID unassigns the issue.
+func (c *Client) AssignIssue(ctx context.Context, key, accountID string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("jira: issue key is required")
+	}
+	var assignee any
+	if accountID != "" {
+		assignee = accountID
+	}
+	payload, _ := json.Marshal(map[string]any{"accountId": assignee})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/rest/api/3/issue/"+key+"/assignee", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: assign failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// HasIssuePermission reports whether the account has the project permission (e.g. "BROWSE_PROJECTS") on the issue.
+func (c *Client) HasIssuePermission(ctx context.Context, key, accountID, permission string) (bool, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || accountID == "" {
+		return false, errors.New("jira: issue key and account are required")
+	}
+	q := neturl.Values{"issueKey": {key}, "accountId": {accountID}, "permissions": {permission}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/user/permission/search?"+q.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return false, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("jira: permission search failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var users []User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(users, func(u User) bool { return u.AccountID == accountID }), nil
+}
+
+// ErrReporterRejected is returned by CreateIssue when Jira rejects the reporter field, e.g. because
+// the bot may not set it or the account cannot be a reporter.
+var ErrReporterRejected = errors.New("jira: create failed, reporter rejected")
+
+// rejectsField reports whether a Jira error response names the field in its "errors" map.
+func rejectsField(data []byte, field string) bool {
+	var resp struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(data, &resp) != nil {
+		return false
+	}
+	_, ok := resp.Errors[field]
+	return ok
+}
+
 // ErrNotFound indicates that the requested issue does not exist or is not accessible.
 var ErrNotFound = errors.New("jira: issue not found")
 
 // IssueStatus is a lightweight view of a Jira issue used for status responses.
 type IssueStatus struct {
-	Key      string
-	Summary  string
-	Status   string
-	Assignee string
-	Priority string
-	Created  time.Time
-	Updated  time.Time
+	Key               string
+	Summary           string
+	Status            string
+	Assignee          string
+	AssigneeAccountID string
+	ReporterAccountID string
+	Priority          string
+	Created           time.Time
+	Updated           time.Time
 }
 
 // GetIssueStatus fetches minimal fields of an issue required to render status.
@@ -176,7 +442,7 @@ func (c *Client) GetIssueStatus(ctx context.Context, key string) (*IssueStatus,
 		return nil, errors.New("jira: issue key is required")
 	}
 
-	url := c.baseURL + "/rest/api/3/issue/" + key + "?fields=summary,status,assignee,priority,created,updated"
+	url := c.baseURL + "/rest/api/3/issue/" + key + "?fields=" + issueStatusFields
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -198,28 +464,40 @@ func (c *Client) GetIssueStatus(ctx context.Context, key string) (*IssueStatus,
 		return nil, fmt.Errorf("jira: get issue failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
 	}
 
-	// Minimal JSON structure for fields we care about
-	var raw struct {
-		Key    string `json:"key"`
-		Fields struct {
-			Summary string `json:"summary"`
-			Status  *struct {
-				Name string `json:"name"`
-			} `json:"status"`
-			Assignee *struct {
-				DisplayName string `json:"displayName"`
-			} `json:"assignee"`
-			Priority *struct {
-				Name string `json:"name"`
-			} `json:"priority"`
-			Created string `json:"created"`
-			Updated string `json:"updated"`
-		} `json:"fields"`
-	}
+	var raw issueStatusJSON
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, err
 	}
+	return raw.toIssueStatus(), nil
+}
 
+// issueStatusFields lists fields requested to build IssueStatus.
+const issueStatusFields = "summary,status,assignee,reporter,priority,created,updated"
+
+// issueStatusJSON is the minimal JSON structure of an issue for fields we care about.
+type issueStatusJSON struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  *struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Assignee *struct {
+			AccountID   string `json:"accountId"`
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Reporter *struct {
+			AccountID string `json:"accountId"`
+		} `json:"reporter"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Created string `json:"created"`
+		Updated string `json:"updated"`
+	} `json:"fields"`
+}
+
+func (raw *issueStatusJSON) toIssueStatus() *IssueStatus {
 	var out IssueStatus
 	out.Key = raw.Key
 	out.Summary = raw.Fields.Summary
@@ -228,14 +506,118 @@ func (c *Client) GetIssueStatus(ctx context.Context, key string) (*IssueStatus,
 	}
 	if raw.Fields.Assignee != nil {
 		out.Assignee = raw.Fields.Assignee.DisplayName
+		out.AssigneeAccountID = raw.Fields.Assignee.AccountID
+	}
+	if raw.Fields.Reporter != nil {
+		out.ReporterAccountID = raw.Fields.Reporter.AccountID
 	}
 	if raw.Fields.Priority != nil {
 		out.Priority = raw.Fields.Priority.Name
 	}
 	out.Created = parseJiraTime(raw.Fields.Created)
 	out.Updated = parseJiraTime(raw.Fields.Updated)
+	return &out
+}
 
-	return &out, nil
+// SearchIssues runs a JQL search and returns one page of issues and the token of the next page ("" if last).
+func (c *Client) SearchIssues(ctx context.Context, jql string, maxResults int, pageToken string) ([]IssueStatus, string, error) {
+	jql = strings.TrimSpace(jql)
+	if jql == "" {
+		return nil, "", errors.New("jira: jql is required")
+	}
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+	request := map[string]any{
+		"jql":        jql,
+		"maxResults": maxResults,
+		"fields":     strings.Split(issueStatusFields, ","),
+	}
+	if pageToken != "" {
+		request["nextPageToken"] = pageToken
+	}
+	payload, _ := json.Marshal(request)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/search/jql", bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, "", fmt.Errorf("%w (%d): %s", errInvalidQuery, resp.StatusCode, truncate(string(data), 512))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("jira: search failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var raw struct {
+		Issues        []issueStatusJSON `json:"issues"`
+		NextPageToken string            `json:"nextPageToken"`
+		IsLast        bool              `json:"isLast"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, "", err
+	}
+	out := make([]IssueStatus, 0, len(raw.Issues))
+	for i := range raw.Issues {
+		out = append(out, *raw.Issues[i].toIssueStatus())
+	}
+	if raw.IsLast {
+		raw.NextPageToken = ""
+	}
+	return out, raw.NextPageToken, nil
+}
+
+// errInvalidQuery is returned by SearchIssues when Jira rejects the JQL, e.g. because of a key that
+// no longer exists.
+var errInvalidQuery = errors.New("jira: search failed, invalid query")
+
+// GetIssuesStatus fetches statuses of many issues with batched JQL searches. A batch that Jira rejects
+// because of a deleted or inaccessible key is fetched issue by issue. Missing issues are absent from
+// the result.
+func (c *Client) GetIssuesStatus(ctx context.Context, keys []string) (map[string]*IssueStatus, error) {
+	const batchSize = 50
+	out := make(map[string]*IssueStatus, len(keys))
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		quoted := make([]string, 0, end-start)
+		for _, key := range keys[start:end] {
+			quoted = append(quoted, strconv.Quote(key))
+		}
+		jql := "key in (" + strings.Join(quoted, ",") + ")"
+		issues, _, err := c.SearchIssues(ctx, jql, batchSize, "")
+		if errors.Is(err, errInvalidQuery) {
+			for _, key := range keys[start:end] {
+				issue, err := c.GetIssueStatus(ctx, key)
+				if errors.Is(err, ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					return out, err
+				}
+				out[issue.Key] = issue
+			}
+			continue
+		}
+		if err != nil {
+			return out, err
+		}
+		for i := range issues {
+			out[issues[i].Key] = &issues[i]
+		}
+	}
+	return out, nil
 }
 
 var jiraTimeLayouts = []string{
@@ -334,6 +716,387 @@ func (c *Client) UpdateIssueDescriptionADF(ctx context.Context, key string, doc
 	return nil
 }
 
+// urlRe matches URLs and account mentions written with MentionMarkup.
+var urlRe = regexp.MustCompile(`https?://[^\s<>"]+|\[~accountid:([^\]\s]+)\]`)
+
+// MentionMarkup returns the text form of a Jira account mention; comments turn it into an ADF mention.
+func MentionMarkup(accountID string) string {
+	return "[~accountid:" + accountID + "]"
+}
+
+var mentionMarkupRe = regexp.MustCompile(`\[~accountid:`)
+
+// EscapeMentionMarkup breaks MentionMarkup in user text with a zero-width space, so it stays text.
+func EscapeMentionMarkup(s string) string {
+	return mentionMarkupRe.ReplaceAllString(s, "[\u200b~accountid:")
+}
+
+// textNodesWithLinks splits a line into ADF text nodes, marking URLs as links and mentions as mention nodes.
+func textNodesWithLinks(line string) []any {
+	var nodes []any
+	last := 0
+	for _, loc := range urlRe.FindAllStringSubmatchIndex(line, -1) {
+		if loc[0] > last {
+			nodes = append(nodes, map[string]any{"type": "text", "text": line[last:loc[0]]})
+		}
+		last = loc[1]
+		if loc[2] >= 0 {
+			nodes = append(nodes, map[string]any{"type": "mention", "attrs": map[string]any{"id": line[loc[2]:loc[3]]}})
+			continue
+		}
+		href := line[loc[0]:loc[1]]
+		nodes = append(nodes, map[string]any{
+			"type":  "text",
+			"text":  href,
+			"marks": []any{map[string]any{"type": "link", "attrs": map[string]any{"href": href}}},
+		})
+	}
+	if last < len(line) {
+		nodes = append(nodes, map[string]any{"type": "text", "text": line[last:]})
+	}
+	return nodes
+}
+
+// AddRemoteLink adds (or updates, by globalID) a web link shown in the issue's "Links" section.
+func (c *Client) AddRemoteLink(ctx context.Context, key, globalID, linkURL, title, iconURL string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || linkURL == "" {
+		return errors.New("jira: issue key and url are required")
+	}
+	object := map[string]any{"url": linkURL, "title": title}
+	if iconURL != "" {
+		object["icon"] = map[string]any{"url16x16": iconURL, "title": title}
+	}
+	request := map[string]any{"object": object}
+	if globalID != "" {
+		request["globalId"] = globalID
+	}
+	payload, _ := json.Marshal(request)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/issue/"+key+"/remotelink", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: add remote link failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// GetWatchers returns account IDs of the issue watchers.
+func (c *Client) GetWatchers(ctx context.Context, key string) ([]string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("jira: issue key is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/issue/"+key+"/watchers", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira: get watchers failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var raw struct {
+		Watchers []struct {
+			AccountID string `json:"accountId"`
+		} `json:"watchers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(raw.Watchers))
+	for _, w := range raw.Watchers {
+		out = append(out, w.AccountID)
+	}
+	return out, nil
+}
+
+// AddWatcher adds the account to the issue watchers.
+func (c *Client) AddWatcher(ctx context.Context, key, accountID string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || accountID == "" {
+		return errors.New("jira: issue key and account id are required")
+	}
+	payload, _ := json.Marshal(accountID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/issue/"+key+"/watchers", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: add watcher failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// RemoveWatcher removes the account from the issue watchers.
+func (c *Client) RemoveWatcher(ctx context.Context, key, accountID string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || accountID == "" {
+		return errors.New("jira: issue key and account id are required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/rest/api/3/issue/"+key+"/watchers?accountId="+neturl.QueryEscape(accountID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: remove watcher failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// GetIssueProperty reads the value of an issue entity property.
+// Returns ErrNotFound if the issue or the property does not exist.
+func (c *Client) GetIssueProperty(ctx context.Context, key, property string) (json.RawMessage, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || property == "" {
+		return nil, errors.New("jira: issue key and property are required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/issue/"+key+"/properties/"+neturl.PathEscape(property), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira: get property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var raw struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.Value, nil
+}
+
+// SetIssueProperty stores value (JSON-encoded, at most 32KB) as an issue entity property.
+func (c *Client) SetIssueProperty(ctx context.Context, key, property string, value any) error {
+	key = strings.TrimSpace(key)
+	if key == "" || property == "" {
+		return errors.New("jira: issue key and property are required")
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/rest/api/3/issue/"+key+"/properties/"+neturl.PathEscape(property), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: set property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// GetProjectProperty reads the value of a project entity property of the configured project.
+// Returns ErrNotFound if the property does not exist.
+func (c *Client) GetProjectProperty(ctx context.Context, property string) (json.RawMessage, error) {
+	if property == "" {
+		return nil, errors.New("jira: property is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/3/project/"+c.projectKey+"/properties/"+neturl.PathEscape(property), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira: get project property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var raw struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return raw.Value, nil
+}
+
+// SetProjectProperty stores value (JSON-encoded, at most 32KB) as a project entity property of the configured project.
+func (c *Client) SetProjectProperty(ctx context.Context, property string, value any) error {
+	if property == "" {
+		return errors.New("jira: property is required")
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/rest/api/3/project/"+c.projectKey+"/properties/"+neturl.PathEscape(property), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: set project property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// DeleteProjectProperty removes a project entity property of the configured project; a missing property
+// is not an error.
+func (c *Client) DeleteProjectProperty(ctx context.Context, property string) error {
+	if property == "" {
+		return errors.New("jira: property is required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/rest/api/3/project/"+c.projectKey+"/properties/"+neturl.PathEscape(property), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: delete project property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// DeleteIssueProperty removes an issue entity property; a missing property is not an error.
+func (c *Client) DeleteIssueProperty(ctx context.Context, key, property string) error {
+	key = strings.TrimSpace(key)
+	if key == "" || property == "" {
+		return errors.New("jira: issue key and property are required")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/rest/api/3/issue/"+key+"/properties/"+neturl.PathEscape(property), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("jira: delete property failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	return nil
+}
+
+// SearchIssueProperties runs a JQL search and returns one page of values of the given entity property
+// by issue key (issues without the property are skipped) and the token of the next page ("" if last).
+func (c *Client) SearchIssueProperties(ctx context.Context, jql, property string, pageToken string) (map[string]json.RawMessage, string, error) {
+	request := map[string]any{
+		"jql":        strings.TrimSpace(jql),
+		"maxResults": 100,
+		"fields":     []string{"key"},
+		"properties": []string{property},
+	}
+	if pageToken != "" {
+		request["nextPageToken"] = pageToken
+	}
+	payload, _ := json.Marshal(request)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/rest/api/3/search/jql", bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("jira: search failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+	}
+	var raw struct {
+		Issues []struct {
+			Key        string                     `json:"key"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"issues"`
+		NextPageToken string `json:"nextPageToken"`
+		IsLast        bool   `json:"isLast"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, "", err
+	}
+	out := make(map[string]json.RawMessage, len(raw.Issues))
+	for _, issue := range raw.Issues {
+		if value, ok := issue.Properties[property]; ok {
+			out[issue.Key] = value
+		}
+	}
+	if raw.IsLast {
+		raw.NextPageToken = ""
+	}
+	return out, raw.NextPageToken, nil
+}
+
 // TransitionIssueToStatus moves an issue to a status reachable via available transitions.
 func (c *Client) TransitionIssueToStatus(ctx context.Context, key, statusName string) error {
 	key = strings.TrimSpace(key)
@@ -380,15 +1143,15 @@ func (c *Client) TransitionIssueToStatus(ctx context.Context, key, statusName st
 	return nil
 }
 
-// AddComment adds a plain text comment to the issue using ADF payload.
-func (c *Client) AddComment(ctx context.Context, key, body string) error {
+// AddComment adds a plain text comment to the issue using ADF payload and returns the comment ID.
+func (c *Client) AddComment(ctx context.Context, key, body string) (string, error) {
 	key = strings.TrimSpace(key)
 	body = strings.TrimSpace(body)
 	if key == "" {
-		return errors.New("jira: issue key is required")
+		return "", errors.New("jira: issue key is required")
 	}
 	if body == "" {
-		return errors.New("jira: comment body is empty")
+		return "", errors.New("jira: comment body is empty")
 	}
 	paragraph := map[string]any{
 		"type":    "paragraph",
@@ -402,10 +1165,7 @@ func (c *Client) AddComment(ctx context.Context, key, body string) error {
 		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
-			"type": "text",
-			"text": line,
-		})
+		paragraph["content"] = append(paragraph["content"].([]any), textNodesWithLinks(line)...)
 	}
 	if len(paragraph["content"].([]any)) == 0 {
 		paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
@@ -423,20 +1183,29 @@ func (c *Client) AddComment(ctx context.Context, key, body string) error {
 	url := c.baseURL + "/rest/api/3/issue/" + key + "/comment"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payload)))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Authorization", c.authHeader)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusCreated {
-		data, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira: add comment failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+		return "", fmt.Errorf("jira: add comment failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
 	}
-	return nil
+	return commentID(data), nil
+}
+
+// commentID returns the ID of the comment in a response of the comment API.
+func commentID(data []byte) string {
+	var comment struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(data, &comment)
+	return comment.ID
 }
 
 // AddCommentReaction adds reaction to a Jira comment to acknowledge processing.
@@ -565,6 +1334,7 @@ type Comment struct {
 	Created      JiraTime    `json:"created"`
 	Updated      JiraTime    `json:"updated"`
 	Author       struct {
+		AccountID   string `json:"accountId"`
 		DisplayName string `json:"displayName"`
 		Email       string `json:"emailAddress"`
 	} `json:"author"`
@@ -574,6 +1344,31 @@ type Comment struct {
 	} `json:"updateAuthor"`
 }
 
+// Mentions returns the accounts mentioned in the comment.
+func (c Comment) Mentions() []string {
+	var ids []string
+	var walk func(node any)
+	walk = func(node any) {
+		switch v := node.(type) {
+		case map[string]any:
+			if v["type"] == "mention" {
+				if attrs, ok := v["attrs"].(map[string]any); ok {
+					if id, _ := attrs["id"].(string); id != "" && !slices.Contains(ids, id) {
+						ids = append(ids, id)
+					}
+				}
+			}
+			walk(v["content"])
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(c.Body.Raw)
+	return ids
+}
+
 // CommentBody captures Jira comment bodies returned either as plain strings or as Atlassian Document (ADF) objects.
 type CommentBody struct {
 	Text string
@@ -612,6 +1407,11 @@ func extractADFPlainText(node any) string {
 		if typ == "hardBreak" {
 			return "\n"
 		}
+		if typ == "mention" {
+			attrs, _ := v["attrs"].(map[string]any)
+			txt, _ := attrs["text"].(string)
+			return txt
+		}
 		if typ == "text" {
 			if txt, _ := v["text"].(string); txt != "" {
 				return txt
@@ -731,6 +1531,7 @@ func (c *Client) AddAttachment(ctx context.Context, key string, filename string,
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("jira: add attachment failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
 	}
+	metrics.AttachmentBytes.Add(float64(len(fileData)))
 	
 	// Parse response to get attachment ID
 	var attachments []struct {
@@ -812,10 +1613,7 @@ func (c *Client) AddCommentWithEmbeddedImages(ctx context.Context, key, body str
 			if strings.TrimSpace(line) == "" {
 				continue
 			}
-			paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
-				"type": "text",
-				"text": line,
-			})
+			paragraph["content"] = append(paragraph["content"].([]any), textNodesWithLinks(line)...)
 		}
 		if len(paragraph["content"].([]any)) == 0 {
 			paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
@@ -885,15 +1683,16 @@ func (c *Client) AddCommentWithEmbeddedImages(ctx context.Context, key, body str
 	return nil
 }
 
-// AddCommentWithEmbeddedFiles adds a comment with embedded files to a Jira issue
-func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body string, files []common.FileInfo) error {
+// AddCommentWithEmbeddedFiles adds a comment with embedded files to a Jira issue and returns the comment
+// ID; fileLabel precedes the link to each file.
+func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body, fileLabel string, files []common.FileInfo) (string, error) {
 	key = strings.TrimSpace(key)
 	body = strings.TrimSpace(body)
 	if key == "" {
-		return errors.New("jira: issue key is required")
+		return "", errors.New("jira: issue key is required")
 	}
 	if body == "" && len(files) == 0 {
-		return errors.New("jira: comment body and files are empty")
+		return "", errors.New("jira: comment body and files are empty")
 	}
 	
 	// First, upload files as attachments
@@ -968,10 +1767,7 @@ func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body stri
 			if strings.TrimSpace(line) == "" {
 				continue
 			}
-			paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
-				"type": "text",
-				"text": line,
-			})
+			paragraph["content"] = append(paragraph["content"].([]any), textNodesWithLinks(line)...)
 		}
 		if len(paragraph["content"].([]any)) == 0 {
 			paragraph["content"] = append(paragraph["content"].([]any), map[string]any{
@@ -991,7 +1787,7 @@ func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body stri
 				"content": []any{
 					map[string]any{
 						"type": "text",
-						"text": "📎 Вложенный файл: ",
+						"text": fileLabel,
 					},
 					map[string]any{
 						"type": "text",
@@ -1029,14 +1825,14 @@ func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body stri
 	url := c.baseURL + "/rest/api/3/issue/" + key + "/comment"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payload)))
 	if err != nil {
-		return err
+		return "", err
 	}
 	req.Header.Set("Authorization", c.authHeader)
 	req.Header.Set("Content-Type", "application/json")
 	
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 	
@@ -1045,8 +1841,8 @@ func (c *Client) AddCommentWithEmbeddedFiles(ctx context.Context, key, body stri
 	fmt.Printf("DEBUG: Response body: %s\n", string(data))
 	
 	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("jira: add comment with files failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
+		return "", fmt.Errorf("jira: add comment with files failed (%d): %s", resp.StatusCode, truncate(string(data), 512))
 	}
 	
-	return nil
+	return commentID(data), nil
 }
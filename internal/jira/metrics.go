@@ -0,0 +1,42 @@
+package jira
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"telegram-bot-jira/internal/metrics"
+)
+
+// instrumentedTransport records every Jira API call in metrics.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	endpoint := endpointLabel(req.URL.Path)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.JiraRequests.Inc(req.Method, endpoint, status)
+	metrics.JiraRequestDuration.Observe(time.Since(start).Seconds(), req.Method, endpoint)
+	return resp, err
+}
+
+// endpointLabel replaces issue keys and ids in the path with {id} to keep label cardinality low:
+// /rest/api/3/issue/ABC-12/comment -> /rest/api/3/issue/{id}/comment.
+func endpointLabel(path string) string {
+	path = strings.TrimPrefix(path, "/rest/api/3")
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.IndexFunc(p, unicode.IsDigit) >= 0 {
+			parts[i] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
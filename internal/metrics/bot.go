@@ -0,0 +1,20 @@
+package metrics
+
+// Bot metrics; the inbound update queue depth gauge is registered by the bot itself. Replies are
+// sent synchronously by the workers, so there is no outbound queue to measure.
+var (
+	TelegramUpdates = NewCounter("jirabot_telegram_updates_total",
+		"Telegram updates processed by update type and handler.", "type", "handler")
+	HandlerErrors = NewCounter("jirabot_handler_errors_total",
+		"Errors returned by update handlers.", "handler")
+	JiraRequests = NewCounter("jirabot_jira_requests_total",
+		"Jira API calls by HTTP method, endpoint and status code.", "method", "endpoint", "status")
+	JiraRequestDuration = NewHistogram("jirabot_jira_request_duration_seconds",
+		"Jira API call latency.", DefBuckets, "method", "endpoint")
+	PollDuration = NewHistogram("jirabot_poll_cycle_duration_seconds",
+		"Duration of one ticket polling cycle.", []float64{.5, 1, 2.5, 5, 10, 30, 60, 120, 300})
+	TicketsTracked = NewGauge("jirabot_tickets_tracked",
+		"Tickets tracked by the bot per Jira status.", "status")
+	AttachmentBytes = NewCounter("jirabot_attachment_bytes_total",
+		"Bytes of attachments transferred from Telegram to Jira.")
+)
@@ -0,0 +1,233 @@
+// Package metrics is a minimal Prometheus-compatible registry: labelled counters,
+// gauges and histograms rendered in the text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type collector interface {
+	write(w io.Writer)
+}
+
+var (
+	regMu      sync.Mutex
+	collectors []collector
+)
+
+func register(c collector) {
+	regMu.Lock()
+	defer regMu.Unlock()
+	collectors = append(collectors, c)
+}
+
+// Handler serves all registered metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		regMu.Lock()
+		list := append([]collector(nil), collectors...)
+		regMu.Unlock()
+		for _, c := range list {
+			c.write(w)
+		}
+	})
+}
+
+// family holds series of one metric keyed by joined label values.
+type family struct {
+	name, help, kind string
+	labels           []string
+	mu               sync.Mutex
+	keys             []string
+}
+
+func (f *family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (f *family) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+}
+
+// labelString renders {a="x",b="y"} with optional extra label (e.g. le).
+func (f *family) labelString(key string, extra ...string) string {
+	var pairs []string
+	if len(f.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, f.labels[i]+"="+strconv.Quote(v))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+"="+strconv.Quote(extra[i+1]))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Vec is a counter or gauge with labels.
+type Vec struct {
+	family
+	values map[string]float64
+}
+
+// NewCounter registers a monotonically increasing counter.
+func NewCounter(name, help string, labels ...string) *Vec {
+	return newVec(name, help, "counter", labels)
+}
+
+// NewGauge registers a gauge that can be set to arbitrary values.
+func NewGauge(name, help string, labels ...string) *Vec {
+	return newVec(name, help, "gauge", labels)
+}
+
+func newVec(name, help, kind string, labels []string) *Vec {
+	v := &Vec{family: family{name: name, help: help, kind: kind, labels: labels}, values: map[string]float64{}}
+	register(v)
+	return v
+}
+
+// Inc adds 1 to the series with the given label values.
+func (v *Vec) Inc(labels ...string) { v.Add(1, labels...) }
+
+// Add adds delta to the series with the given label values.
+func (v *Vec) Add(delta float64, labels ...string) {
+	k := v.key(labels)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.values[k]; !ok {
+		v.keys = append(v.keys, k)
+	}
+	v.values[k] += delta
+}
+
+// Set sets the gauge series with the given label values.
+func (v *Vec) Set(value float64, labels ...string) {
+	k := v.key(labels)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.values[k]; !ok {
+		v.keys = append(v.keys, k)
+	}
+	v.values[k] = value
+}
+
+// Reset removes all series, used for gauges rebuilt from scratch (e.g. tickets per status).
+func (v *Vec) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = nil
+	v.values = map[string]float64{}
+}
+
+func (v *Vec) write(w io.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.header(w)
+	keys := append([]string(nil), v.keys...)
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, v.labelString(k), formatFloat(v.values[k]))
+	}
+}
+
+// GaugeFunc is an unlabelled gauge whose value is read on every scrape.
+type GaugeFunc struct {
+	family
+	fn func() float64
+}
+
+// NewGaugeFunc registers a gauge backed by fn.
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{family: family{name: name, help: help, kind: "gauge"}, fn: fn}
+	register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.header(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.fn()))
+}
+
+// DefBuckets are latency buckets in seconds.
+var DefBuckets = []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+type histogramSeries struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	family
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+// NewHistogram registers a histogram with the given upper bounds.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		family:  family{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+	register(h)
+	return h
+}
+
+// Observe records one value for the series with the given label values.
+func (h *Histogram) Observe(value float64, labels ...string) {
+	k := h.key(labels)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+		h.keys = append(h.keys, k)
+	}
+	for i, b := range h.buckets {
+		if value <= b {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w)
+	keys := append([]string(nil), h.keys...)
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := h.series[k]
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", formatFloat(b)), s.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelString(k, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelString(k), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelString(k), s.count)
+	}
+}
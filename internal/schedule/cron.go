@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute hour day-of-month month day-of-week.
+// Fields accept "*", numbers, ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n".
+// Day of week is 0-7, both 0 and 7 meaning Sunday.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// Parse parses a cron expression like "0 9 * * 1-5".
+func Parse(spec string) (*Cron, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: expected 5 fields, got %d in %q", len(fields), spec)
+	}
+	var c Cron
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if c.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if c.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if c.month, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if c.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return &c, nil
+}
+
+// Matches reports whether t (in its own location) falls on a scheduled minute.
+// As in classic cron, when both day fields are restricted either of them may match.
+func (c *Cron) Matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 || c.hour&(1<<uint(t.Hour())) == 0 || c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("schedule: bad step in %q", part)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("schedule: bad value in %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("schedule: bad range in %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("schedule: %q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
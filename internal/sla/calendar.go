@@ -0,0 +1,152 @@
+package sla
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar describes business hours: working weekdays, a daily window and holidays in a timezone.
+type Calendar struct {
+	loc      *time.Location
+	days     [7]bool
+	start    int // minutes from midnight
+	end      int // minutes from midnight
+	holidays map[string]bool
+}
+
+// maxCalendarDays bounds calendar walks so that a misconfiguration can't hang the bot.
+const maxCalendarDays = 3 * 366
+
+// ParseCalendar builds a calendar from "09:00-18:00", weekdays "1-5" (0 is Sunday),
+// an IANA timezone and comma-separated holiday dates "2006-01-02". Empty hours mean round the clock.
+func ParseCalendar(hours, days, tz, holidays string) (*Calendar, error) {
+	c := &Calendar{loc: time.Local, start: 0, end: 24 * 60, holidays: make(map[string]bool)}
+	if tz = strings.TrimSpace(tz); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, err
+		}
+		c.loc = loc
+	}
+	if hours = strings.TrimSpace(hours); hours != "" {
+		from, to, ok := strings.Cut(hours, "-")
+		if !ok {
+			return nil, fmt.Errorf("sla: bad business hours %q", hours)
+		}
+		var err error
+		if c.start, err = parseClock(from); err != nil {
+			return nil, err
+		}
+		if c.end, err = parseClock(to); err != nil {
+			return nil, err
+		}
+		if c.start >= c.end {
+			return nil, fmt.Errorf("sla: business hours %q end before start", hours)
+		}
+	}
+	if days = strings.TrimSpace(days); days == "" {
+		days = "0-6"
+	}
+	for _, part := range strings.Split(days, ",") {
+		a, b, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		lo, err := strconv.Atoi(a)
+		if err != nil {
+			return nil, fmt.Errorf("sla: bad business day %q", part)
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(b); err != nil {
+				return nil, fmt.Errorf("sla: bad business days %q", part)
+			}
+		}
+		if lo < 0 || hi > 7 || lo > hi {
+			return nil, fmt.Errorf("sla: business days %q out of range 0-7", part)
+		}
+		for d := lo; d <= hi; d++ {
+			c.days[d%7] = true
+		}
+	}
+	for _, h := range strings.Split(holidays, ",") {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", h); err != nil {
+			return nil, fmt.Errorf("sla: bad holiday %q", h)
+		}
+		c.holidays[h] = true
+	}
+	return c, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		if strings.TrimSpace(s) == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, fmt.Errorf("sla: bad time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// window returns business hours of the day containing t; ok is false on days off.
+func (c *Calendar) window(t time.Time) (start, end time.Time, ok bool) {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, c.loc)
+	if !c.days[t.Weekday()] || c.holidays[midnight.Format("2006-01-02")] {
+		return time.Time{}, time.Time{}, false
+	}
+	return midnight.Add(time.Duration(c.start) * time.Minute), midnight.Add(time.Duration(c.end) * time.Minute), true
+}
+
+func nextMidnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// Add returns the moment when d of business time has passed since t.
+func (c *Calendar) Add(t time.Time, d time.Duration) time.Time {
+	t = t.In(c.loc)
+	for i := 0; i < maxCalendarDays; i++ {
+		start, end, ok := c.window(t)
+		if !ok || !t.Before(end) {
+			t = nextMidnight(t)
+			continue
+		}
+		if t.Before(start) {
+			t = start
+		}
+		if left := end.Sub(t); d <= left {
+			return t.Add(d)
+		} else {
+			d -= left
+		}
+		t = nextMidnight(t)
+	}
+	return t
+}
+
+// Between returns business time elapsed from a to b, zero if b is not after a.
+func (c *Calendar) Between(a, b time.Time) time.Duration {
+	var total time.Duration
+	a, b = a.In(c.loc), b.In(c.loc)
+	for i := 0; i < maxCalendarDays && a.Before(b); i++ {
+		start, end, ok := c.window(a)
+		if ok && a.Before(end) {
+			if a.Before(start) {
+				a = start
+			}
+			stop := end
+			if b.Before(stop) {
+				stop = b
+			}
+			if stop.After(a) {
+				total += stop.Sub(a)
+			}
+		}
+		a = nextMidnight(a)
+	}
+	return total
+}
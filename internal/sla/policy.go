@@ -0,0 +1,187 @@
+package sla
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"telegram-bot-jira/internal/config"
+)
+
+// Flags remember which SLA alerts were already sent for a ticket.
+const (
+	FirstResponseWarned uint8 = 1 << iota
+	FirstResponseBreached
+	ResolutionWarned
+	ResolutionBreached
+)
+
+// Targets are SLA durations in business time; zero means the target is not tracked.
+type Targets struct {
+	FirstResponse time.Duration
+	Resolution    time.Duration
+}
+
+// Policy is the SLA applied to a ticket.
+type Policy struct {
+	Targets
+	WarnBefore time.Duration
+	Calendar   *Calendar
+}
+
+// Times are the tracked moments of a ticket.
+type Times struct {
+	CreatedAt       time.Time
+	FirstResponseAt time.Time
+	ResolvedAt      time.Time
+}
+
+// Status is a policy evaluated for a ticket at a moment.
+type Status struct {
+	FirstResponseDue      time.Time
+	FirstResponseAt       time.Time
+	FirstResponseBreached bool
+	FirstResponseWarning  bool
+	ResolutionDue         time.Time
+	ResolvedAt            time.Time
+	ResolutionBreached    bool
+	ResolutionWarning     bool
+}
+
+// Policies resolves the policy of a ticket by chat and priority.
+type Policies struct {
+	calendar   *Calendar
+	warnBefore time.Duration
+	def        Targets
+	byPriority map[string]Targets
+	chats      map[int64]chatPolicy
+}
+
+type chatPolicy struct {
+	def        Targets
+	byPriority map[string]Targets
+}
+
+// NewPolicies parses SLA_* settings and chat overrides. Priority overrides have the form
+// "Highest=1h/8h;High=2h/16h" (first response / resolution).
+func NewPolicies(cfg config.Config) (*Policies, error) {
+	cal, err := ParseCalendar(cfg.SLABusinessHours, cfg.SLABusinessDays, cfg.SLATimezone, cfg.SLAHolidays)
+	if err != nil {
+		return nil, err
+	}
+	p := &Policies{calendar: cal, chats: make(map[int64]chatPolicy)}
+	if p.warnBefore, err = parseDuration(cfg.SLAWarnBefore); err != nil {
+		return nil, err
+	}
+	if p.def, err = parseTargets(cfg.SLAFirstResponse, cfg.SLAResolution); err != nil {
+		return nil, err
+	}
+	if p.byPriority, err = parsePriorityTargets(cfg.SLAPriorities); err != nil {
+		return nil, err
+	}
+	for chatID, chat := range cfg.Chats {
+		cp := chatPolicy{byPriority: make(map[string]Targets)}
+		if cp.def, err = parseTargets(chat.SLA.FirstResponse, chat.SLA.Resolution); err != nil {
+			return nil, fmt.Errorf("chat %d: %w", chatID, err)
+		}
+		for priority, t := range chat.SLA.Priorities {
+			targets, err := parseTargets(t.FirstResponse, t.Resolution)
+			if err != nil {
+				return nil, fmt.Errorf("chat %d: %w", chatID, err)
+			}
+			cp.byPriority[strings.ToLower(priority)] = targets
+		}
+		p.chats[chatID] = cp
+	}
+	return p, nil
+}
+
+// For returns the policy of a ticket or nil if no SLA targets apply.
+// Lookup order: chat priority, chat default, global priority, global default.
+func (p *Policies) For(chatID int64, priority string) *Policy {
+	if p == nil {
+		return nil
+	}
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	candidates := []Targets{}
+	if chat, ok := p.chats[chatID]; ok {
+		candidates = append(candidates, chat.byPriority[priority], chat.def)
+	}
+	candidates = append(candidates, p.byPriority[priority], p.def)
+	for _, t := range candidates {
+		if t.FirstResponse > 0 || t.Resolution > 0 {
+			return &Policy{Targets: t, WarnBefore: p.warnBefore, Calendar: p.calendar}
+		}
+	}
+	return nil
+}
+
+// Evaluate computes deadlines and breach/warning state of a ticket at now.
+func (p *Policy) Evaluate(t Times, now time.Time) Status {
+	s := Status{FirstResponseAt: t.FirstResponseAt, ResolvedAt: t.ResolvedAt}
+	if t.CreatedAt.IsZero() {
+		return s
+	}
+	if p.FirstResponse > 0 {
+		s.FirstResponseDue = p.Calendar.Add(t.CreatedAt, p.FirstResponse)
+		s.FirstResponseBreached, s.FirstResponseWarning = p.check(s.FirstResponseDue, t.FirstResponseAt, now)
+	}
+	if p.Resolution > 0 {
+		s.ResolutionDue = p.Calendar.Add(t.CreatedAt, p.Resolution)
+		s.ResolutionBreached, s.ResolutionWarning = p.check(s.ResolutionDue, t.ResolvedAt, now)
+	}
+	return s
+}
+
+func (p *Policy) check(due, doneAt time.Time, now time.Time) (breached, warning bool) {
+	if !doneAt.IsZero() {
+		return doneAt.After(due), false
+	}
+	if !now.Before(due) {
+		return true, false
+	}
+	return false, p.WarnBefore > 0 && p.Calendar.Between(now, due) <= p.WarnBefore
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s = strings.TrimSpace(s); s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("sla: %w", err)
+	}
+	return d, nil
+}
+
+func parseTargets(firstResponse, resolution string) (Targets, error) {
+	var t Targets
+	var err error
+	if t.FirstResponse, err = parseDuration(firstResponse); err != nil {
+		return t, err
+	}
+	if t.Resolution, err = parseDuration(resolution); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+func parsePriorityTargets(s string) (map[string]Targets, error) {
+	out := make(map[string]Targets)
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		priority, durations, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("sla: bad priority target %q", item)
+		}
+		firstResponse, resolution, _ := strings.Cut(durations, "/")
+		t, err := parseTargets(firstResponse, resolution)
+		if err != nil {
+			return nil, err
+		}
+		out[strings.ToLower(strings.TrimSpace(priority))] = t
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// JiraLink links a Telegram user to the Jira account they confirmed with /link_jira.
+type JiraLink struct {
+	User      TelegramUser `json:"user"`
+	AccountID string       `json:"account_id"`
+	Name      string       `json:"name,omitempty"` // Jira display name
+	Email     string       `json:"email,omitempty"`
+	LinkedAt  time.Time    `json:"linked_at"`
+}
+
+// IdentityStore holds Telegram-to-Jira account links. A Telegram user and a Jira account
+// are each linked at most once. It also keeps the users who get notifications about their
+// tickets in the private chat with the bot.
+type IdentityStore struct {
+	mu      sync.RWMutex
+	links   []JiraLink
+	private []int64 // Telegram user IDs
+	dirty   bool
+}
+
+func NewIdentityStore() *IdentityStore {
+	return &IdentityStore{}
+}
+
+// Init replaces the links and private notification users, e.g. with the ones loaded from storage.
+func (s *IdentityStore) Init(links []JiraLink, private []int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = slices.Clone(links)
+	s.private = slices.Clone(private)
+	s.dirty = false
+}
+
+// List returns a copy of all links.
+func (s *IdentityStore) List() []JiraLink {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.links)
+}
+
+// Account returns the Jira account linked to the user, "" if there is none.
+func (s *IdentityStore) Account(user TelegramUser) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(user); i >= 0 {
+		return s.links[i].AccountID
+	}
+	return ""
+}
+
+// Link returns the link of the user.
+func (s *IdentityStore) Link(user TelegramUser) (JiraLink, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(user); i >= 0 {
+		return s.links[i], true
+	}
+	return JiraLink{}, false
+}
+
+// User returns the Telegram user linked to the Jira account.
+func (s *IdentityStore) User(accountID string) (TelegramUser, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, link := range s.links {
+		if link.AccountID == accountID {
+			return link.User, true
+		}
+	}
+	return TelegramUser{}, false
+}
+
+// Add links the user to the account, replacing earlier links of either of them.
+func (s *IdentityStore) Add(link JiraLink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = slices.DeleteFunc(s.links, func(l JiraLink) bool {
+		return l.AccountID == link.AccountID || l.User.Is(link.User.ID, link.User.Username)
+	})
+	s.links = append(s.links, link)
+	s.dirty = true
+}
+
+// Remove unlinks the user; reports whether the user was linked.
+func (s *IdentityStore) Remove(user TelegramUser) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(user)
+	if i < 0 {
+		return false
+	}
+	s.links = slices.Delete(s.links, i, i+1)
+	s.dirty = true
+	return true
+}
+
+// PrivateUsers returns the users who get notifications in the private chat.
+func (s *IdentityStore) PrivateUsers() []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.private)
+}
+
+// NotifiesPrivately reports whether notifications about the user's tickets go to the private chat.
+func (s *IdentityStore) NotifiesPrivately(userID int64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return userID != 0 && slices.Contains(s.private, userID)
+}
+
+// SetNotifyPrivately switches the user's notifications to the private chat or back to group chats.
+func (s *IdentityStore) SetNotifyPrivately(userID int64, private bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if userID == 0 || slices.Contains(s.private, userID) == private {
+		return
+	}
+	if private {
+		s.private = append(s.private, userID)
+	} else {
+		s.private = slices.DeleteFunc(s.private, func(id int64) bool { return id == userID })
+	}
+	s.dirty = true
+}
+
+// DirtyAndReset reports whether links changed since the previous call.
+func (s *IdentityStore) DirtyAndReset() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirty := s.dirty
+	s.dirty = false
+	return dirty
+}
+
+// MarkDirty schedules the links to be saved again, e.g. after a failed save.
+func (s *IdentityStore) MarkDirty() {
+	s.mu.Lock()
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+func (s *IdentityStore) index(user TelegramUser) int {
+	if user.IsZero() {
+		return -1
+	}
+	return slices.IndexFunc(s.links, func(l JiraLink) bool {
+		return l.User.Is(user.ID, user.Username)
+	})
+}
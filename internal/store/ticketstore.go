@@ -1,23 +1,93 @@
-﻿package store
+package store
 
 import (
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
 
 type CreatedTicket struct {
-	Key             string
-	Name            string
-	Status          string
-	ChatID          int64
-	CreatorUsername string
-	LastCommentAt   time.Time
+	Key             string         `json:"key"`
+	Name            string         `json:"name"`
+	Summary         string         `json:"summary,omitempty"` // last seen Jira summary, renames the ticket when edited there
+	Status          string         `json:"status"`
+	Assignee        string         `json:"assignee"`                // last seen Jira assignee, for change notifications
+	AssigneeSeen    bool           `json:"assignee_seen,omitempty"` // Assignee was recorded; unset for tickets stored before it was
+	ChatID          int64          `json:"chat_id"`
+	ThreadID        int            `json:"thread_id"` // forum topic, 0 for General or a regular chat
+	Creator         TelegramUser   `json:"creator"`
+	Watchers        []TelegramUser `json:"watchers,omitempty"`        // users notified besides the creator
+	PrivateChatID   int64          `json:"private_chat_id,omitempty"` // opt-in of earlier versions, moved to the creator on start
+	LastCommentAt   time.Time      `json:"last_comment_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	FirstResponseAt time.Time      `json:"first_response_at"` // first comment of an agent in Jira
+	ResolvedAt      time.Time      `json:"resolved_at"`       // moved to a "ready" status
+	SLAFlags        uint8          `json:"sla_flags"`         // SLA alerts already sent, see sla flags
+	SourceMessageID int            `json:"source_message_id"` // Telegram message that created the ticket
+	MessageID       int            `json:"message_id"`        // bot's ticket message that notifications reply to
+	Tracked         bool           `json:"tracked,omitempty"` // an existing issue attached with /track, without SLA
+}
+
+// TelegramUser identifies a Telegram user. ID is 0 while only the username is known,
+// e.g. for a reporter given as @username or for tickets stored before IDs were kept.
+type TelegramUser struct {
+	ID       int64  `json:"id,omitempty"`
+	Username string `json:"username,omitempty"` // without @
+	Name     string `json:"name,omitempty"`     // display name
+}
+
+// IsZero reports whether nothing is known about the user.
+func (u TelegramUser) IsZero() bool {
+	return u.ID == 0 && u.Username == ""
+}
+
+// Is reports whether u is the user with the Telegram ID or, while the ID is unknown, the username.
+func (u TelegramUser) Is(id int64, username string) bool {
+	if u.ID != 0 && id != 0 {
+		return u.ID == id
+	}
+	return username != "" && strings.EqualFold(u.Username, username)
+}
+
+// WatchedBy reports whether the user is among the ticket watchers.
+func (t CreatedTicket) WatchedBy(userID int64, username string) bool {
+	return slices.ContainsFunc(t.Watchers, func(w TelegramUser) bool { return w.Is(userID, username) })
+}
+
+// Equal reports whether two tickets hold the same data.
+func (t CreatedTicket) Equal(o CreatedTicket) bool {
+	if !slices.Equal(t.Watchers, o.Watchers) {
+		return false
+	}
+	t.Watchers, o.Watchers = nil, nil
+	return reflect.DeepEqual(t, o)
+}
+
+// UTC returns a copy with all timestamps in UTC, as persisted.
+func (t CreatedTicket) UTC() CreatedTicket {
+	t.LastCommentAt = t.LastCommentAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.FirstResponseAt = t.FirstResponseAt.UTC()
+	t.ResolvedAt = t.ResolvedAt.UTC()
+	return t
+}
+
+// CreatedBy reports whether the ticket was created by the user with the Telegram ID or username.
+func (t CreatedTicket) CreatedBy(userID int64, username string) bool {
+	return t.Creator.Is(userID, username)
 }
 
 type TicketStore struct {
 	mu    sync.RWMutex
 	byKey map[string]CreatedTicket
 	dirty bool
+	// dirtyKeys are tickets changed or deleted since the last DirtyKeysAndReset.
+	dirtyKeys map[string]bool
+	// users are identities already applied by UpdateUser, by Telegram ID; reset when tickets with
+	// new creators or watchers come in.
+	users map[int64]TelegramUser
 }
 
 func (s *TicketStore) Has(key string) {
@@ -25,10 +95,10 @@ func (s *TicketStore) Has(key string) {
 }
 
 func NewTicketStore() *TicketStore {
-	return &TicketStore{byKey: make(map[string]CreatedTicket), dirty: false}
+	return &TicketStore{byKey: make(map[string]CreatedTicket), dirty: false, dirtyKeys: make(map[string]bool)}
 }
 
-func (s *TicketStore) Add(chatID int64, key, status, name, username string) {
+func (s *TicketStore) Add(chatID int64, key, status, name string, creator TelegramUser) {
 	if s == nil || key == "" {
 		return
 	}
@@ -38,8 +108,13 @@ func (s *TicketStore) Add(chatID int64, key, status, name, username string) {
 	ticket.Name = name
 	ticket.Status = status
 	ticket.ChatID = chatID
-	ticket.CreatorUsername = username
+	ticket.Creator = creator
+	if ticket.CreatedAt.IsZero() {
+		ticket.CreatedAt = time.Now()
+	}
 	s.byKey[key] = ticket
+	s.markDirty(key)
+	s.users = nil
 	s.mu.Unlock()
 }
 
@@ -50,7 +125,7 @@ func (s *TicketStore) Delete(key string) {
 	s.mu.Lock()
 	if _, ok := s.byKey[key]; ok {
 		delete(s.byKey, key)
-		s.dirty = true
+		s.markDirty(key)
 	}
 	s.mu.Unlock()
 }
@@ -76,6 +151,7 @@ func (s *TicketStore) Init(tickets []CreatedTicket) {
 	for _, ticket := range tickets {
 		s.byKey[ticket.Key] = ticket
 	}
+	s.users = nil
 	s.mu.Unlock()
 }
 
@@ -93,6 +169,47 @@ func (s *TicketStore) ListAll() []CreatedTicket {
 	return out
 }
 
+// ChatThread identifies a chat or a forum topic in it.
+type ChatThread struct {
+	ChatID   int64
+	ThreadID int
+}
+
+// Threads returns distinct chats and forum topics that have tickets.
+func (s *TicketStore) Threads() []ChatThread {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := make(map[ChatThread]bool)
+	var out []ChatThread
+	for _, ticket := range s.byKey {
+		thread := ChatThread{ChatID: ticket.ChatID, ThreadID: ticket.ThreadID}
+		if !seen[thread] {
+			seen[thread] = true
+			out = append(out, thread)
+		}
+	}
+	return out
+}
+
+// ListByThread returns tickets that belong to the forum topic (0 — General or a regular chat).
+func (s *TicketStore) ListByThread(chatID int64, threadID int) []CreatedTicket {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []CreatedTicket
+	for _, ticket := range s.byKey {
+		if ticket.ChatID == chatID && ticket.ThreadID == threadID {
+			out = append(out, ticket)
+		}
+	}
+	return out
+}
+
 // ListByChatID returns tickets that belong to the provided chat.
 func (s *TicketStore) ListByChatID(chatID int64) []CreatedTicket {
 	if s == nil {
@@ -109,12 +226,29 @@ func (s *TicketStore) ListByChatID(chatID int64) []CreatedTicket {
 	return out
 }
 
+// ListByCreator returns tickets created by the user, matched by Telegram user ID or username.
+func (s *TicketStore) ListByCreator(userID int64, username string) []CreatedTicket {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []CreatedTicket
+	for _, ticket := range s.byKey {
+		if ticket.CreatedBy(userID, username) {
+			out = append(out, ticket)
+		}
+	}
+	return out
+}
+
 func (s *TicketStore) AddOrUpdate(ticket *CreatedTicket) {
 	if s == nil || ticket == nil {
 		return
 	}
 	s.mu.Lock()
 	s.byKey[ticket.Key] = *ticket
+	s.users = nil
 	s.mu.Unlock()
 }
 
@@ -130,7 +264,7 @@ func (s *TicketStore) UpdateLastCommentAt(key string, lastCommentAt time.Time) {
 	}
 	if !lastCommentAt.IsZero() && !lastCommentAt.Equal(ticket.LastCommentAt) {
 		ticket.LastCommentAt = lastCommentAt
-		s.dirty = true
+		s.markDirty(key)
 		s.byKey[key] = ticket
 	}
 }
@@ -152,7 +286,101 @@ func (s *TicketStore) UpdateStatus(key, status string) {
 	}
 	s.byKey[key] = ticket
 	if changed {
-		s.dirty = true
+		s.markDirty(key)
+	}
+}
+
+// Update applies fn to the stored ticket; fn reports whether it changed anything worth persisting.
+func (s *TicketStore) Update(key string, fn func(ticket *CreatedTicket) bool) {
+	if s == nil || fn == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ticket, b := s.byKey[key]
+	if !b {
+		return
+	}
+	if fn(&ticket) {
+		s.markDirty(key)
+	}
+	s.byKey[key] = ticket
+}
+
+// Watch adds the user to the ticket watchers; false if the ticket is unknown or already watched.
+func (s *TicketStore) Watch(key string, user TelegramUser) bool {
+	if s == nil || user.IsZero() {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ticket, ok := s.byKey[key]
+	if !ok || ticket.WatchedBy(user.ID, user.Username) {
+		return false
+	}
+	// copy: snapshots returned by Get and List share the backing array
+	ticket.Watchers = append(slices.Clip(ticket.Watchers), user)
+	s.byKey[key] = ticket
+	s.markDirty(key)
+	s.users = nil
+	return true
+}
+
+// Unwatch removes the user from the ticket watchers; false if the user was not watching.
+func (s *TicketStore) Unwatch(key string, user TelegramUser) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ticket, ok := s.byKey[key]
+	if !ok || !ticket.WatchedBy(user.ID, user.Username) {
+		return false
+	}
+	watchers := slices.DeleteFunc(slices.Clone(ticket.Watchers), func(w TelegramUser) bool { return w.Is(user.ID, user.Username) })
+	if len(watchers) == 0 {
+		watchers = nil
+	}
+	ticket.Watchers = watchers
+	s.byKey[key] = ticket
+	s.markDirty(key)
+	return true
+}
+
+// UpdateUser refreshes the identity of a user seen in Telegram on tickets created or watched by them:
+// fills in the ID of users known only by username and follows username and name changes. Users
+// seen with the same identity before are skipped without scanning the tickets.
+func (s *TicketStore) UpdateUser(user TelegramUser) {
+	if s == nil || user.ID == 0 {
+		return
+	}
+	s.mu.RLock()
+	known := s.users[user.ID] == user
+	s.mu.RUnlock()
+	if known {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.users == nil {
+		s.users = make(map[int64]TelegramUser)
+	}
+	s.users[user.ID] = user
+	for key, ticket := range s.byKey {
+		changed := false
+		if ticket.Creator.Is(user.ID, user.Username) && ticket.Creator != user {
+			ticket.Creator = user
+			changed = true
+		}
+		if i := slices.IndexFunc(ticket.Watchers, func(w TelegramUser) bool { return w.Is(user.ID, user.Username) }); i >= 0 && ticket.Watchers[i] != user {
+			ticket.Watchers = slices.Clone(ticket.Watchers)
+			ticket.Watchers[i] = user
+			changed = true
+		}
+		if changed {
+			s.byKey[key] = ticket
+			s.markDirty(key)
+		}
 	}
 }
 
@@ -167,3 +395,55 @@ func (s *TicketStore) DirtyAndReset() bool {
 	s.mu.Unlock()
 	return d
 }
+
+// MarkDirty forces the next DirtyAndReset (and DirtyKeysAndReset for keys) to report changes,
+// e.g. to retry a failed save.
+func (s *TicketStore) MarkDirty(keys ...string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.dirty = true
+	for _, key := range keys {
+		s.dirtyKeys[key] = true
+	}
+	s.mu.Unlock()
+}
+
+// DirtyKeysAndReset returns keys of tickets changed or deleted since the previous call.
+func (s *TicketStore) DirtyKeysAndReset() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.dirtyKeys))
+	for key := range s.dirtyKeys {
+		keys = append(keys, key)
+	}
+	s.dirtyKeys = make(map[string]bool)
+	return keys
+}
+
+func (s *TicketStore) markDirty(key string) {
+	s.dirty = true
+	s.dirtyKeys[key] = true
+}
+
+// Merge applies changes that came from persisted state without marking the store dirty.
+func (s *TicketStore) Merge(upserts []CreatedTicket, deletes []string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ticket := range upserts {
+		s.byKey[ticket.Key] = ticket
+	}
+	if len(upserts) > 0 {
+		s.users = nil
+	}
+	for _, key := range deletes {
+		delete(s.byKey, key)
+	}
+}
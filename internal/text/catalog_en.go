@@ -0,0 +1,228 @@
+package text
+
+// messagesEN is the English message catalog.
+var messagesEN = map[string]string{
+	// Common
+	"anchor.reply_jira":   "Reply to this message to answer",
+	"anchor.reply_status": "‼️ To add information to the request, be sure to REPLY to this message‼️",
+	"user.fallback":       "user",
+	"date.unknown":        "Not set",
+	"status.unknown":      "Unknown",
+	"assignee.none":       "Unassigned",
+
+	// Commands
+	"command.create_issue":   "Create a Jira issue",
+	"command.status_issue":   "Check a Jira issue status",
+	"command.my_tickets":     "My tickets in all chats",
+	"command.notify_private": "Notifications about my tickets in private",
+	"command.watch":          "Follow a ticket",
+	"command.unwatch":        "Unfollow a ticket",
+	"command.assign":         "Assign a ticket to yourself or @user",
+	"command.cancel":         "Cancel ticket creation",
+	"command.track":          "Attach an existing Jira issue to the chat",
+	"command.untrack":        "Detach an attached Jira issue",
+	"command.search":         "Search Jira issues by text or JQL",
+	"command.link_jira":      "Link your Jira account",
+	"command.unlink_jira":    "Unlink your Jira account",
+	"command.help":           "How to use the bot",
+	"command.start":          "Get started",
+
+	// Buttons
+	"button.reopen":         "Reopen",
+	"button.refresh_status": "Refresh status",
+	"button.follow":         "🔔 Follow",
+	"button.back":           "← Back",
+	"button.cancel":         "Cancel",
+	"button.create":         "✅ Create",
+	"button.merge":          "➕ Add as comment to %s",
+	"button.create_anyway":  "Create new anyway",
+	"button.prev":           "← Prev",
+	"button.next":           "Next →",
+
+	// Telegram
+	"error.unknown":             "unknown error",
+	"error.create_ticket":       "Failed to create the ticket",
+	"error.create_ticket_debug": "Failed to create the ticket.\n\nDetails:\n`%s`",
+	"error.reopen_failed":       "Failed to reopen ticket %s: %v",
+	"error.get_status_failed":   "Failed to get ticket %s: %v",
+
+	"watch.usage":     "Specify a ticket: <code>/watch KEY-123</code>, or reply with the command to a ticket message.",
+	"watch.on":        "🔔 You are following ticket <code>%s</code>: you will be mentioned on new comments and status changes.",
+	"watch.off":       "🔕 You no longer follow ticket <code>%s</code>.",
+	"watch.toast_on":  "🔔 You are following %s",
+	"watch.toast_off": "🔕 You no longer follow %s",
+	"watch.failed":    "Failed to change the subscription to ticket %s: %v",
+
+	"link.usage":          "Link your Jira account: send <code>/link_jira email@company.com</code>, then <code>/link_jira CODE</code> from the Jira email.",
+	"link.status":         "🔗 Telegram is linked to the Jira account <b>%s</b>.",
+	"link.status_email":   "🔗 Telegram is linked to the Jira account <b>%s</b> (%s).",
+	"link.code_sent":      "✉️ A confirmation code was sent to %s. Send it with <code>/link_jira CODE</code> within 15 minutes.",
+	"link.not_configured": "Linking Jira accounts is not configured.",
+	"link.user_not_found": "No Jira account with this email was found.",
+	"link.too_soon":       "⏳ A code was requested recently or too many wrong codes were sent. Try again in a few minutes.",
+	"link.failed":         "Failed to send the confirmation code: %s",
+	"link.confirmed":      "✅ Jira account <b>%s</b> linked: your comments from Telegram will mention your Jira account, and Jira mentions reach you in Telegram.",
+	"link.bad_code":       "Wrong or expired code. Request a new one: <code>/link_jira email@company.com</code>",
+	"link.unlinked":       "Jira account unlinked.",
+	"link.not_linked":     "No Jira account is linked.",
+	"assign.usage":        "Specify a ticket: <code>/assign KEY-123</code> for yourself, <code>/assign KEY-123 @user</code> for another user.",
+	"assign.done":         "👤 Ticket <code>%s</code> is assigned to %s.",
+	"assign.not_linked":   "%s has no linked Jira account: /link_jira in a private chat with the bot.",
+	"assign.failed":       "Failed to assign ticket %s: %v",
+
+	"wizard.summary":     "New ticket, step %d of %d.\nReply to this message with a short summary of the problem.",
+	"wizard.placeholder": "What happened?",
+	"wizard.type":        "New ticket, step %d of %d.\nChoose the ticket type:",
+	"wizard.priority":    "New ticket, step %d of %d.\nChoose the priority:",
+	"wizard.component":   "New ticket, step %d of %d.\nChoose the component:",
+	"wizard.confirm":     "Create the ticket?",
+	"wizard.cancelled":   "Ticket creation cancelled.",
+	"wizard.not_yours":   "This ticket is being created by another user",
+	"wizard.expired":     "This form is outdated, start again with /create_issue",
+
+	"track.usage":         "Specify an issue: <code>/track KEY-123</code>.",
+	"track.other_project": "Issue <code>%s</code> is not in project %s.",
+	"track.already_here":  "Ticket <code>%s</code> is already tracked in this chat.",
+	"track.already_other": "Ticket <code>%s</code> is tracked in another chat.",
+	"track.not_linked":    "Link your Jira account first: /link_jira in a private chat with the bot.",
+	"track.forbidden":     "Your Jira account cannot view issue <code>%s</code>.",
+	"track.failed":        "Failed to attach issue %s: %v",
+	"track.done":          "📌 Issue <code>%s</code> is attached to the chat: new comments and status changes will be posted here.",
+	"untrack.usage":       "Specify an issue: <code>/untrack KEY-123</code>.",
+	"untrack.not_tracked": "Issue <code>%s</code> was not attached to this chat with /track.",
+	"untrack.done":        "Issue <code>%s</code> is detached from the chat.",
+
+	"search.usage":       "Send <code>/search text</code>, an issue key or JQL, e.g. <code>/search status = Open</code>.",
+	"search.title":       "Results for “%s”",
+	"search.page":        "page %d",
+	"search.empty":       "Nothing found for “%s”.",
+	"search.failed":      "Search failed: %v",
+	"search.expired":     "These results are outdated, repeat /search",
+	"search.need_link":   "Link your Jira account with /link_jira to search issues here.",
+	"search.inline_desc": "%s · %s",
+
+	"duplicate.title":        "Similar open tickets found",
+	"duplicate.hint":         "Add your report to one of them, or create a new ticket.",
+	"duplicate.merged":       "📎 Added as a comment to ticket <a href=\"%s\">%s</a>.",
+	"duplicate.merge_failed": "Failed to add a comment to ticket %s: %v",
+
+	"ticket.created":            "Issue created",
+	"ticket.topic_name":         "%s · %s",
+	"ticket.topic_created":      "🧵 A dedicated topic was created for ticket <b>%s</b>.",
+	"ticket.topic_created_link": "🧵 A dedicated topic was created for ticket <b>%s</b>: <a href=\"%s\">open</a>",
+	"ticket.closed":             "Ticket closed",
+	"ticket.closed_mention":     "%s, the ticket is closed.",
+	"ticket.changed":            "Ticket updated",
+	"ticket.changed_mention":    "%s, your request has been updated.",
+	"ticket.not_yours":          "⚠️ Ticket <code>%s</code> was created by another user",
+	"ticket.not_found":          "Ticket <code>%s</code> not found",
+	"ticket.not_from_bot":       "⚠️ Ticket <code>%s</code> was not created by this bot. Attach it to the chat: <code>/track %s</code>",
+	"ticket.too_old_reopen":     "⏳ Ticket <code>%s</code> is too old to be reopened. Create a new one with /create_issue.",
+	"ticket.title":              "Request from Telegram",
+	"ticket.title_chat":         "Request from Telegram \"%s\"",
+
+	"my_tickets.header":       "🗂 <b>Your tickets</b>",
+	"my_tickets.empty":        "You have not created any tickets yet.",
+	"my_tickets.chat_unknown": "Unavailable chat",
+	"my_tickets.notify_on":    "🔔 Notifications about your tickets come here. Move them back to chats: /notify_private off",
+	"my_tickets.notify_off":   "🔕 Notifications come to the chats. Receive them here: /notify_private on",
+	"my_tickets.footer":       "Tap a ticket to open its card; reply to the card to add a comment.",
+	"notify_private.on":       "🔔 Notifications about your tickets now come to this chat.",
+	"notify_private.off":      "🔕 Notifications about your tickets come to the chats again.",
+	"tickets.header":          "🗂 <b>Tickets of this chat</b>",
+	"tickets.empty":           "No tickets have been created in this chat yet.",
+	"tickets.total":           "Total: %s",
+	"tickets.ready_header":    "<b>Done tickets</b>",
+	"tickets.footer":          "Send <code>/status_issue TEC-123</code> to see the details of a ticket.",
+
+	"comment.header":      "📬 Comment on <code>%s</code>",
+	"comment.from":        "👤 from %s for %s",
+	"comment.from_anyone": "👤 from %s",
+
+	// Field labels
+	"label.summary":   "Summary",
+	"label.key":       "Key",
+	"label.link":      "Link",
+	"label.status":    "Status",
+	"label.assignee":  "Assignee",
+	"label.created":   "Created",
+	"label.updated":   "Updated",
+	"label.priority":  "Priority",
+	"label.comments":  "Comments",
+	"label.author":    "Reporter",
+	"label.type":      "Type",
+	"label.component": "Component",
+
+	"timeline.header":   "🕘 Recent events:",
+	"timeline.status":   "status: %s → %s",
+	"timeline.assignee": "assignee: %s → %s",
+	"timeline.priority": "priority: %s → %s",
+	"timeline.comments": "comments: %s → %s",
+	"digest.header":     "📋 <b>Open tickets on %s</b>",
+	"digest.total":      "Open: %s",
+	"digest.today":      "today",
+	"digest.stale":      "⚠️ Not updated for %s or longer: %s",
+
+	"sla.first_response": "First response",
+	"sla.resolution":     "Resolution",
+	"sla.due":            "due %s",
+	"sla.done":           "done %s",
+	"sla.warning":        "SLA breach is approaching",
+	"sla.breached":       "SLA breached",
+
+	// Help
+	"help.title":          "ℹ️ <b>How to use the bot</b>",
+	"help.private_intro":  "The bot turns group conversations into Jira issues. Add it to your work chat to create tickets there; here you can follow the tickets you created with /my_tickets.",
+	"help.commands":       "<b>Commands</b>",
+	"help.create":         "<b>Creating a ticket</b>",
+	"help.create_command": "• <code>/create_issue text @reporter</code> creates a ticket from the latest chat messages; the text becomes the summary, <code>@reporter</code> is who gets notified.",
+	"help.create_mention": "• A message starting with @%s does the same.",
+	"help.create_files":   "• Photos, documents and videos from the history are attached to the issue.",
+	"help.conversation":   "<b>Talking about a ticket</b>",
+	"help.reply":          "• Reply to a bot message marked “%s” or “%s” and your text is posted to Jira as a comment.",
+	"help.reply_media":    "• A reply with photos or files (albums too) attaches them to the comment.",
+	"help.jira_prefix":    "• A Jira comment starting with <code>/tg</code> is forwarded to this chat.",
+	"help.digest":         "• <code>/status_issue</code> without a key lists all tickets of the chat.",
+	"help.settings":       "<b>Settings</b>",
+	"help.project":        "📁 Jira project: <code>%s</code>",
+	"help.reopen_on":      "🔁 Closed tickets can be reopened with the “%s” button.",
+	"help.reopen_off":     "🔁 Reopening tickets is disabled.",
+	"help.error_chat":     "🚨 Bot errors are copied to a service chat.",
+	"help.language":       "🌐 Language: English",
+	"bot.added":           "👋 Hi everyone! Requests from this chat can now be turned into Jira issues.",
+	"bot.added_chat":      "👋 Hi everyone! Requests from “%s” can now be turned into Jira issues.",
+
+	// Jira
+	"jira.reopen_chat":              "👤 User: %s in chat %s\nRequested a reopen.",
+	"jira.reopen":                   "👤 User: %s requested a reopen.",
+	"jira.link_subject":             "Telegram link code",
+	"jira.link_body":                "Telegram user %s is linking this Jira account to the bot. Confirmation code: %s\n\nIf it was not you, ignore this email.",
+	"jira.message_from_tg":          "💬 Message from Telegram",
+	"jira.author":                   "👤 Author: %s",
+	"jira.message_link":             "🔗 Message: %s",
+	"jira.remote_link":              "Telegram: %s",
+	"jira.attachment":               "📎 Attached file: ",
+	"jira.reply_to":                 "🔁 In reply to: ",
+	"jira.description_topic":        "Subject: %s",
+	"jira.history_empty":            "Message history is empty",
+	"jira.history_heading":          "Telegram conversation",
+	"jira.generated":                "Generated automatically from a Telegram conversation",
+	"jira.aggregate_heading":        "Bot tickets",
+	"jira.aggregate_note":           "Generated by the bot; edits to this table are overwritten. The data is stored in the issue property \"%s\".",
+	"jira.aggregate_key":            "Key",
+	"jira.aggregate_status":         "Status",
+	"jira.aggregate_name":           "Summary",
+	"jira.aggregate_chat":           "Chat ID",
+	"jira.aggregate_author":         "Reporter",
+	"jira.aggregate_comment":        "Last comment",
+	"jira.aggregate_created":        "Created",
+	"jira.aggregate_first_response": "First response",
+	"jira.aggregate_resolved":       "Resolved",
+	"jira.aggregate_sla":            "SLA",
+}
+
+// pluralsEN holds one/other word forms.
+var pluralsEN = map[string][]string{
+	"ticket": {"ticket", "tickets"},
+	"day":    {"day", "days"},
+}
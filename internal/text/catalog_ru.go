@@ -0,0 +1,228 @@
+package text
+
+// messagesRU — русский каталог сообщений.
+var messagesRU = map[string]string{
+	// Общие
+	"anchor.reply_jira":   "Для ответа прикрепите это сообщение",
+	"anchor.reply_status": "‼️ Чтобы добавить информацию к заявке, ОБЯЗАТЕЛЬНО ответьте на это сообщение‼️",
+	"user.fallback":       "пользователь",
+	"date.unknown":        "Не указана",
+	"status.unknown":      "Неизвестно",
+	"assignee.none":       "Не назначен",
+
+	// Команды
+	"command.create_issue":   "Создать Jira задачу",
+	"command.status_issue":   "Узнать статус Jira задачи",
+	"command.my_tickets":     "Мои тикеты во всех чатах",
+	"command.notify_private": "Уведомления по моим тикетам в личку",
+	"command.watch":          "Подписаться на тикет",
+	"command.unwatch":        "Отписаться от тикета",
+	"command.assign":         "Назначить тикет на себя или @пользователя",
+	"command.cancel":         "Отменить создание тикета",
+	"command.track":          "Подключить существующую задачу Jira к чату",
+	"command.untrack":        "Отключить подключённую задачу Jira",
+	"command.search":         "Поиск задач Jira по тексту или JQL",
+	"command.link_jira":      "Привязать аккаунт Jira",
+	"command.unlink_jira":    "Отвязать аккаунт Jira",
+	"command.help":           "Как пользоваться ботом",
+	"command.start":          "Начать работу с ботом",
+
+	// Кнопки
+	"button.reopen":         "Переоткрыть",
+	"button.refresh_status": "Обновить статус",
+	"button.follow":         "🔔 Следить",
+	"button.back":           "← Назад",
+	"button.cancel":         "Отмена",
+	"button.create":         "✅ Создать",
+	"button.merge":          "➕ Добавить комментарием в %s",
+	"button.create_anyway":  "Всё равно создать новый",
+	"button.prev":           "← Назад",
+	"button.next":           "Дальше →",
+
+	// Telegram
+	"error.unknown":             "неизвестная ошибка",
+	"error.create_ticket":       "Не удалось создать тикет",
+	"error.create_ticket_debug": "Не удалось создать тикет.\n\nДетали:\n`%s`",
+	"error.reopen_failed":       "Не удалось переоткрыть тикет %s: %v",
+	"error.get_status_failed":   "Не удалось получить информацию по тикету %s: %v",
+
+	"watch.usage":     "Укажите тикет: <code>/watch KEY-123</code> или ответьте командой на сообщение с тикетом.",
+	"watch.on":        "🔔 Вы подписаны на тикет <code>%s</code>: будем упоминать вас при новых комментариях и смене статуса.",
+	"watch.off":       "🔕 Вы отписались от тикета <code>%s</code>.",
+	"watch.toast_on":  "🔔 Вы следите за %s",
+	"watch.toast_off": "🔕 Вы больше не следите за %s",
+	"watch.failed":    "Не удалось изменить подписку на тикет %s: %v",
+
+	"link.usage":          "Привязка аккаунта Jira: отправьте <code>/link_jira email@company.com</code>, затем <code>/link_jira КОД</code> из письма Jira.",
+	"link.status":         "🔗 Telegram привязан к аккаунту Jira <b>%s</b>.",
+	"link.status_email":   "🔗 Telegram привязан к аккаунту Jira <b>%s</b> (%s).",
+	"link.code_sent":      "✉️ Код подтверждения отправлен на %s. Пришлите его командой <code>/link_jira КОД</code> в течение 15 минут.",
+	"link.not_configured": "Привязка аккаунтов Jira не настроена.",
+	"link.user_not_found": "Аккаунт Jira с такой почтой не найден.",
+	"link.too_soon":       "⏳ Код уже недавно запрашивали или было слишком много неверных кодов. Попробуйте через несколько минут.",
+	"link.failed":         "Не удалось отправить код подтверждения: %s",
+	"link.confirmed":      "✅ Аккаунт Jira <b>%s</b> привязан: в ваших комментариях из Telegram будет упоминание вашего аккаунта Jira, а упоминания в Jira дойдут до Telegram.",
+	"link.bad_code":       "Неверный или просроченный код. Запросите новый: <code>/link_jira email@company.com</code>",
+	"link.unlinked":       "Аккаунт Jira отвязан.",
+	"link.not_linked":     "Аккаунт Jira не привязан.",
+	"assign.usage":        "Укажите тикет: <code>/assign KEY-123</code> — на себя, <code>/assign KEY-123 @user</code> — на пользователя.",
+	"assign.done":         "👤 Тикет <code>%s</code> назначен на %s.",
+	"assign.not_linked":   "У %s нет привязанного аккаунта Jira: /link_jira в личке с ботом.",
+	"assign.failed":       "Не удалось назначить тикет %s: %v",
+
+	"wizard.summary":     "Новый тикет, шаг %d из %d.\nОтветьте на это сообщение кратким описанием проблемы.",
+	"wizard.placeholder": "Что случилось?",
+	"wizard.type":        "Новый тикет, шаг %d из %d.\nВыберите тип тикета:",
+	"wizard.priority":    "Новый тикет, шаг %d из %d.\nВыберите приоритет:",
+	"wizard.component":   "Новый тикет, шаг %d из %d.\nВыберите компонент:",
+	"wizard.confirm":     "Создать тикет?",
+	"wizard.cancelled":   "Создание тикета отменено.",
+	"wizard.not_yours":   "Этот тикет создаёт другой пользователь",
+	"wizard.expired":     "Форма устарела, начните заново с /create_issue",
+
+	"track.usage":         "Укажите задачу: <code>/track KEY-123</code>.",
+	"track.other_project": "Задача <code>%s</code> не из проекта %s.",
+	"track.already_here":  "Тикет <code>%s</code> уже отслеживается в этом чате.",
+	"track.already_other": "Тикет <code>%s</code> отслеживается в другом чате.",
+	"track.not_linked":    "Сначала привяжите аккаунт Jira: /link_jira в личке с ботом.",
+	"track.forbidden":     "Ваш аккаунт Jira не может просматривать задачу <code>%s</code>.",
+	"track.failed":        "Не удалось подключить задачу %s: %v",
+	"track.done":          "📌 Задача <code>%s</code> подключена к чату: новые комментарии и смена статуса будут приходить сюда.",
+	"untrack.usage":       "Укажите задачу: <code>/untrack KEY-123</code>.",
+	"untrack.not_tracked": "Задача <code>%s</code> не подключалась к этому чату через /track.",
+	"untrack.done":        "Задача <code>%s</code> отключена от чата.",
+
+	"search.usage":       "Отправьте <code>/search текст</code>, ключ задачи или JQL, например <code>/search status = Open</code>.",
+	"search.title":       "Результаты по запросу «%s»",
+	"search.page":        "страница %d",
+	"search.empty":       "По запросу «%s» ничего не найдено.",
+	"search.failed":      "Не удалось выполнить поиск: %v",
+	"search.expired":     "Результаты устарели, повторите /search",
+	"search.need_link":   "Чтобы искать задачи здесь, привяжите аккаунт Jira командой /link_jira.",
+	"search.inline_desc": "%s · %s",
+
+	"duplicate.title":        "Найдены похожие открытые тикеты",
+	"duplicate.hint":         "Добавьте своё сообщение в один из них или создайте новый тикет.",
+	"duplicate.merged":       "📎 Добавлено комментарием в тикет <a href=\"%s\">%s</a>.",
+	"duplicate.merge_failed": "Не удалось добавить комментарий в тикет %s: %v",
+
+	"ticket.created":            "Задача успешно создана",
+	"ticket.topic_name":         "%s · %s",
+	"ticket.topic_created":      "🧵 Для тикета <b>%s</b> создана отдельная тема.",
+	"ticket.topic_created_link": "🧵 Для тикета <b>%s</b> создана отдельная тема: <a href=\"%s\">перейти</a>",
+	"ticket.closed":             "Тикет закрыт",
+	"ticket.closed_mention":     "%s, тикет закрыт.",
+	"ticket.changed":            "Тикет обновлён",
+	"ticket.changed_mention":    "%s, по вашей заявке есть изменения.",
+	"ticket.not_yours":          "⚠️ Тикет <code>%s</code> создан другим пользователем",
+	"ticket.not_found":          "Тикет <code>%s</code> не найден",
+	"ticket.not_from_bot":       "⚠️ Тикет <code>%s</code> был создан не в этом боте. Подключите его к чату: <code>/track %s</code>",
+	"ticket.too_old_reopen":     "⏳ Тикет <code>%s</code> слишком старый, его нельзя переоткрыть. Создайте новый через /create_issue.",
+	"ticket.title":              "Обращение из Telegram",
+	"ticket.title_chat":         "Обращение из Telegram \"%s\"",
+
+	"my_tickets.header":       "🗂 <b>Ваши тикеты</b>",
+	"my_tickets.empty":        "Вы ещё не создали ни одного тикета.",
+	"my_tickets.chat_unknown": "Чат недоступен",
+	"my_tickets.notify_on":    "🔔 Уведомления по вашим тикетам приходят сюда. Вернуть их в чаты: /notify_private off",
+	"my_tickets.notify_off":   "🔕 Уведомления приходят в чаты. Получать их здесь: /notify_private on",
+	"my_tickets.footer":       "Нажмите на тикет, чтобы открыть карточку; ответьте на карточку, чтобы добавить комментарий.",
+	"notify_private.on":       "🔔 Уведомления о ваших тикетах теперь приходят в личку.",
+	"notify_private.off":      "🔕 Уведомления о ваших тикетах снова приходят в чаты.",
+	"tickets.header":          "🗂 <b>Тикеты этого чата</b>",
+	"tickets.empty":           "В этом чате ещё не создано ни одного тикета.",
+	"tickets.total":           "Всего: %s",
+	"tickets.ready_header":    "<b>Тикеты в статусе «Готов»</b>",
+	"tickets.footer":          "Отправьте <code>/status_issue TEC-123</code>, чтобы посмотреть детали конкретного тикета.",
+
+	"comment.header":      "📬 Комментарий по <code>%s</code>",
+	"comment.from":        "👤 от %s для %s",
+	"comment.from_anyone": "👤 от %s",
+
+	// Подписи полей
+	"label.summary":   "Название",
+	"label.key":       "Ключ",
+	"label.link":      "Ссылка",
+	"label.status":    "Статус",
+	"label.assignee":  "Ответственный",
+	"label.created":   "Создан",
+	"label.updated":   "Обновлён",
+	"label.priority":  "Приоритет",
+	"label.comments":  "Комментариев",
+	"label.author":    "Автор",
+	"label.type":      "Тип",
+	"label.component": "Компонент",
+
+	"timeline.header":   "🕘 Последние события:",
+	"timeline.status":   "статус: %s → %s",
+	"timeline.assignee": "исполнитель: %s → %s",
+	"timeline.priority": "приоритет: %s → %s",
+	"timeline.comments": "комментарии: %s → %s",
+	"digest.header":     "📋 <b>Открытые тикеты на %s</b>",
+	"digest.total":      "Открыто: %s",
+	"digest.today":      "сегодня",
+	"digest.stale":      "⚠️ Без обновлений %s и дольше: %s",
+
+	"sla.first_response": "Первый ответ",
+	"sla.resolution":     "Решение",
+	"sla.due":            "до %s",
+	"sla.done":           "выполнено %s",
+	"sla.warning":        "SLA скоро будет нарушен",
+	"sla.breached":       "SLA нарушен",
+
+	// Справка
+	"help.title":          "ℹ️ <b>Как пользоваться ботом</b>",
+	"help.private_intro":  "Бот создаёт задачи в Jira из переписки в группе. Добавьте его в рабочий чат, чтобы создавать тикеты; здесь можно следить за своими тикетами через /my_tickets.",
+	"help.commands":       "<b>Команды</b>",
+	"help.create":         "<b>Создание тикета</b>",
+	"help.create_command": "• <code>/create_issue текст @автор</code> — создаёт тикет из последних сообщений чата, текст становится названием, <code>@автор</code> — кого уведомлять по тикету.",
+	"help.create_mention": "• То же самое — сообщение, начинающееся с @%s.",
+	"help.create_files":   "• Фото, документы и видео из истории прикрепляются к задаче.",
+	"help.conversation":   "<b>Переписка по тикету</b>",
+	"help.reply":          "• Ответьте (reply) на сообщение бота с пометкой «%s» или «%s» — текст уйдёт комментарием в Jira.",
+	"help.reply_media":    "• Ответ с фото или файлами (в том числе альбомом) прикрепит их к комментарию.",
+	"help.jira_prefix":    "• Комментарий в Jira, начинающийся с <code>/tg</code>, бот перешлёт в этот чат.",
+	"help.digest":         "• <code>/status_issue</code> без ключа покажет все тикеты чата.",
+	"help.settings":       "<b>Настройки</b>",
+	"help.project":        "📁 Проект Jira: <code>%s</code>",
+	"help.reopen_on":      "🔁 Закрытые тикеты можно переоткрыть кнопкой «%s».",
+	"help.reopen_off":     "🔁 Переоткрытие тикетов выключено.",
+	"help.error_chat":     "🚨 Ошибки бота дублируются в служебный чат.",
+	"help.language":       "🌐 Язык: русский",
+	"bot.added":           "👋 Всем привет! Теперь обращения из этого чата можно оформлять задачами в Jira.",
+	"bot.added_chat":      "👋 Всем привет! Теперь обращения из «%s» можно оформлять задачами в Jira.",
+
+	// Jira
+	"jira.reopen_chat":              "👤 Пользователь: %s в чате %s\nЗапросил переоткрытие.",
+	"jira.reopen":                   "👤 Пользователь: %s запросил переоткрытие.",
+	"jira.link_subject":             "Код привязки Telegram",
+	"jira.link_body":                "Пользователь Telegram %s привязывает этот аккаунт Jira к боту. Код подтверждения: %s\n\nЕсли это не вы, просто проигнорируйте письмо.",
+	"jira.message_from_tg":          "💬 Сообщение из Telegram",
+	"jira.author":                   "👤 Автор: %s",
+	"jira.message_link":             "🔗 Сообщение: %s",
+	"jira.remote_link":              "Telegram: %s",
+	"jira.attachment":               "📎 Вложенный файл: ",
+	"jira.reply_to":                 "🔁 Ответ на: ",
+	"jira.description_topic":        "Тема: %s",
+	"jira.history_empty":            "История сообщений пуста",
+	"jira.history_heading":          "Чат из переписки в Telegram",
+	"jira.generated":                "Сформировано автоматически из переписки Telegram",
+	"jira.aggregate_heading":        "Список тикетов бота",
+	"jira.aggregate_note":           "Сгенерировано ботом, правки таблицы перезаписываются. Данные хранятся в свойстве задачи «%s».",
+	"jira.aggregate_key":            "Ключ",
+	"jira.aggregate_status":         "Статус",
+	"jira.aggregate_name":           "Название",
+	"jira.aggregate_chat":           "Чат ID",
+	"jira.aggregate_author":         "Автор",
+	"jira.aggregate_comment":        "Последний коммент",
+	"jira.aggregate_created":        "Создан",
+	"jira.aggregate_first_response": "Первый ответ",
+	"jira.aggregate_resolved":       "Решён",
+	"jira.aggregate_sla":            "SLA",
+}
+
+// pluralsRU — формы слов для 1, 2–4 и 5+ (one, few, many).
+var pluralsRU = map[string][]string{
+	"ticket": {"тикет", "тикета", "тикетов"},
+	"day":    {"день", "дня", "дней"},
+}
@@ -0,0 +1,126 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lang is a language of user-facing texts.
+type Lang string
+
+const (
+	LangRU Lang = "ru"
+	LangEN Lang = "en"
+)
+
+// Langs lists all languages that have a message catalog.
+var Langs = []Lang{LangRU, LangEN}
+
+var defaultLang = LangRU
+
+var catalogs = map[Lang]map[string]string{
+	LangRU: messagesRU,
+	LangEN: messagesEN,
+}
+
+var pluralCatalogs = map[Lang]map[string][]string{
+	LangRU: pluralsRU,
+	LangEN: pluralsEN,
+}
+
+// SetDefaultLang sets the language used when neither chat nor user language is known.
+func SetDefaultLang(code string) {
+	if lang := ParseLang(code); lang != "" {
+		defaultLang = lang
+	}
+}
+
+// DefaultLang returns the configured fallback language.
+func DefaultLang() Lang {
+	return defaultLang
+}
+
+// ParseLang maps a language code like "en" or "en-US" to a supported Lang, or "" if unsupported.
+func ParseLang(code string) Lang {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	lang := Lang(code)
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	return ""
+}
+
+// ResolveLang returns the first supported language among codes (chat setting, user language, ...)
+// or the default language.
+func ResolveLang(codes ...string) Lang {
+	for _, code := range codes {
+		if lang := ParseLang(code); lang != "" {
+			return lang
+		}
+	}
+	return defaultLang
+}
+
+// T returns the catalog message for key formatted with args.
+// Missing keys fall back to the default language and then to the key itself.
+func T(lang Lang, key string, args ...any) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[defaultLang][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Plural returns the word form of key agreeing with n, e.g. "тикет", "тикета", "тикетов".
+func Plural(lang Lang, n int, key string) string {
+	forms, ok := pluralCatalogs[lang][key]
+	if !ok {
+		lang = defaultLang
+		forms, ok = pluralCatalogs[lang][key]
+	}
+	if !ok || len(forms) == 0 {
+		return key
+	}
+	i := pluralIndex(lang, n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return forms[i]
+}
+
+// PluralN returns n followed by the agreeing word form, e.g. "5 тикетов".
+func PluralN(lang Lang, n int, key string) string {
+	return fmt.Sprintf("%d %s", n, Plural(lang, n, key))
+}
+
+// pluralIndex follows CLDR rules: ru has one/few/many forms, en has one/other.
+func pluralIndex(lang Lang, n int) int {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case LangRU:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
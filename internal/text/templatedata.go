@@ -0,0 +1,202 @@
+package text
+
+import (
+	"html/template"
+	"time"
+
+	"telegram-bot-jira/internal/sla"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Модель данных шаблонов. Строковые поля передаются в шаблоны как есть:
+// в *.html.tmpl экранирование HTML выполняет html/template, в *.txt.tmpl и *.adf.tmpl текст не экранируется.
+// Поля типа template.HTML — готовая разметка (упоминания пользователей), они не экранируются.
+
+// TicketCreatedData — данные шаблона ticket_created.html.tmpl.
+type TicketCreatedData struct {
+	Title string // название тикета
+	Key   string // ключ задачи, например TEC-123
+	URL   string // ссылка на задачу в Jira
+}
+
+// TicketClosedData — данные шаблона ticket_closed.html.tmpl.
+type TicketClosedData struct {
+	Key     string        // ключ задачи
+	Status  string        // статус, в который перешла задача
+	URL     string        // ссылка на задачу в Jira
+	Creator template.HTML // упоминание автора обращения, пусто — без упоминания
+}
+
+// TicketChangedData — данные шаблона ticket_changed.html.tmpl.
+type TicketChangedData struct {
+	Key             string        // ключ задачи
+	URL             string        // ссылка на задачу в Jira
+	FromStatus      string        // прежний статус, пусто если статус не менялся
+	ToStatus        string        // новый статус
+	AssigneeChanged bool          // сменился ответственный
+	FromAssignee    string        // прежний ответственный, пусто если не был назначен
+	ToAssignee      string        // новый ответственный, пусто если снят
+	Creator         template.HTML // упоминание автора обращения, пусто — без упоминания
+}
+
+// TicketStatusData — данные шаблона ticket_status.html.tmpl.
+type TicketStatusData struct {
+	Key      string          // ключ задачи
+	Summary  string          // название (из хранилища бота, иначе из Jira)
+	Status   string          // статус в Jira
+	Assignee string          // ответственный, пусто если не назначен
+	Priority string          // приоритет в Jira
+	Created  time.Time       // дата создания
+	Updated  time.Time       // дата последнего обновления
+	Author   template.HTML   // упоминание автора обращения
+	SLA      *sla.Status     // сроки SLA, nil если SLA не настроен
+	Comments int             // число комментариев в Jira, 0 — не показывать
+	Timeline []TimelineEvent // последние события живой карточки, старые первыми
+}
+
+// TimelineEvent — событие в мини-ленте карточки тикета.
+type TimelineEvent struct {
+	At   time.Time
+	Kind string // status, assignee, priority, comments
+	From string // прежнее значение (для comments — прежнее число)
+	To   string // новое значение
+}
+
+// SLAAlertData — данные шаблона sla_alert.html.tmpl.
+type SLAAlertData struct {
+	Key      string
+	Summary  string
+	URL      string
+	Target   string    // "first_response" или "resolution"
+	Due      time.Time // срок по SLA
+	Breached bool      // срок уже нарушен, иначе — предупреждение
+}
+
+// WizardConfirmData — данные шаблона wizard_confirm.html.tmpl.
+type WizardConfirmData struct {
+	Summary   string
+	Type      string // пусто, если шаг пропущен
+	Priority  string
+	Component string
+}
+
+// DuplicatesData — данные шаблона duplicates.html.tmpl.
+type DuplicatesData struct {
+	Tickets []IssueLine
+}
+
+// SearchResultsData — данные шаблона search_results.html.tmpl.
+type SearchResultsData struct {
+	Query  string // запрос /search
+	Page   int    // номер страницы, с 1
+	Issues []IssueLine
+}
+
+// IssueLine — задача Jira в списке или карточке issue_card.html.tmpl.
+type IssueLine struct {
+	Key      string
+	Summary  string
+	Status   string
+	Assignee string // пусто, если не назначена
+	Priority string
+	URL      string
+}
+
+// TicketLine — строка списка тикетов.
+type TicketLine struct {
+	Key    string
+	Name   string
+	Status string
+}
+
+// TicketsDigestData — данные шаблона tickets_digest.html.tmpl.
+type TicketsDigestData struct {
+	ChatTitle string       // название чата
+	Total     int          // общее число тикетов
+	Active    []TicketLine // тикеты в работе
+	Ready     []TicketLine // тикеты в статусе «Готов»
+}
+
+// MyTicketsChat — тикеты автора в одном чате.
+type MyTicketsChat struct {
+	Title   string       // название чата, пусто если неизвестно
+	Tickets []TicketLine // тикеты чата
+}
+
+// MyTicketsData — данные шаблона my_tickets.html.tmpl.
+type MyTicketsData struct {
+	Total         int             // общее число тикетов автора
+	Chats         []MyTicketsChat // тикеты по чатам
+	NotifyPrivate bool            // уведомления приходят в личку
+}
+
+// DigestTicket — открытый тикет в ежедневном дайджесте.
+type DigestTicket struct {
+	Key      string
+	Name     string
+	Assignee string // пусто, если не назначен
+	URL      string // ссылка на задачу в Jira
+	AgeDays  int    // сколько полных дней назад создан
+	Stale    bool   // давно не обновлялся
+}
+
+// DigestGroup — тикеты дайджеста с одинаковым статусом.
+type DigestGroup struct {
+	Status  string
+	Tickets []DigestTicket
+}
+
+// DailyDigestData — данные шаблона daily_digest.html.tmpl.
+type DailyDigestData struct {
+	Date       time.Time     // момент формирования в часовом поясе дайджеста
+	Total      int           // число открытых тикетов
+	Groups     []DigestGroup // тикеты, сгруппированные по статусу
+	StaleDays  int           // порог «давно не обновлялся» в днях
+	StaleCount int           // сколько тикетов превысили порог
+}
+
+// CommentJiraToTelegramData — данные шаблона comment_jira_to_telegram.html.tmpl.
+type CommentJiraToTelegramData struct {
+	Key           string        // ключ задачи
+	TicketAuthor  template.HTML // упоминание автора обращения, пусто если автор неизвестен
+	CommentAuthor string        // имя автора комментария в Jira
+	Text          string        // текст комментария без префикса /tg
+}
+
+// HelpData — данные шаблона help.html.tmpl.
+type HelpData struct {
+	HelpInfo
+	Commands []tgbotapi.BotCommand // команды, доступные в текущем типе чата
+}
+
+// JiraCommentFromTelegramData — данные шаблона jira_comment_from_telegram.txt.tmpl.
+type JiraCommentFromTelegramData struct {
+	ChatTitle string // название чата
+	Author    string // отображаемое имя автора сообщения
+	Text      string // текст сообщения
+	ReplyTo   string // текст комментария из Jira, на который ответили, или пусто
+	URL       string // ссылка t.me на сообщение, может быть пустой
+}
+
+// JiraCommentReopenData — данные шаблона jira_comment_reopen.txt.tmpl.
+type JiraCommentReopenData struct {
+	User      string // отображаемое имя пользователя
+	ChatTitle string // название чата
+}
+
+// DescriptionMessage — сообщение из истории чата для описания задачи.
+type DescriptionMessage struct {
+	Date   time.Time
+	Author string
+	Text   string
+	URL    string // ссылка t.me на сообщение, может быть пустой
+}
+
+// IssueDescriptionData — данные шаблона issue_description.adf.tmpl.
+type IssueDescriptionData struct {
+	Title     string               // тема задачи
+	ChatTitle string               // название чата
+	ChatURL   string               // ссылка на чат, может быть пустой
+	Messages  []DescriptionMessage // история сообщений с текстом
+}
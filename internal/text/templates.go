@@ -0,0 +1,251 @@
+package text
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"telegram-bot-jira/internal/sla"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Шаблоны сообщений. *.html.tmpl — HTML для Telegram (html/template),
+// *.txt.tmpl — простой текст для Jira, *.adf.tmpl — блоки ADF для Jira (text/template).
+const (
+	tmplTicketCreated           = "ticket_created.html.tmpl"
+	tmplTicketClosed            = "ticket_closed.html.tmpl"
+	tmplTicketStatus            = "ticket_status.html.tmpl"
+	tmplTicketsDigest           = "tickets_digest.html.tmpl"
+	tmplCommentJiraToTelegram   = "comment_jira_to_telegram.html.tmpl"
+	tmplHelp                    = "help.html.tmpl"
+	tmplJiraCommentFromTelegram = "jira_comment_from_telegram.txt.tmpl"
+	tmplJiraCommentReopen       = "jira_comment_reopen.txt.tmpl"
+	tmplIssueDescription        = "issue_description.adf.tmpl"
+	tmplDailyDigest             = "daily_digest.html.tmpl"
+	tmplSLAAlert                = "sla_alert.html.tmpl"
+	tmplTicketChanged           = "ticket_changed.html.tmpl"
+	tmplMyTickets               = "my_tickets.html.tmpl"
+	tmplWizardConfirm           = "wizard_confirm.html.tmpl"
+	tmplDuplicates              = "duplicates.html.tmpl"
+	tmplSearchResults           = "search_results.html.tmpl"
+	tmplIssueCard               = "issue_card.html.tmpl"
+)
+
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// templateSamples holds sample data per template, used to validate templates at startup.
+var templateSamples = map[string]any{
+	tmplTicketCreated: TicketCreatedData{Title: "Title", Key: "KEY-1", URL: "https://example.com/browse/KEY-1"},
+	tmplTicketClosed:  TicketClosedData{Key: "KEY-1", Status: "Done", URL: "https://example.com/browse/KEY-1", Creator: "@user"},
+	tmplTicketStatus: TicketStatusData{Key: "KEY-1", Summary: "Title", Status: "Open", Priority: "High", Created: time.Now(), Updated: time.Now(), Author: "@user",
+		SLA:      &sla.Status{FirstResponseDue: time.Now(), FirstResponseAt: time.Now(), ResolutionDue: time.Now(), ResolutionBreached: true},
+		Comments: 2, Timeline: []TimelineEvent{{At: time.Now(), Kind: "status", From: "Open", To: "Done"}, {At: time.Now(), Kind: "comments", From: "1", To: "2"}}},
+	tmplTicketChanged: TicketChangedData{Key: "KEY-1", URL: "https://example.com/browse/KEY-1", FromStatus: "Open", ToStatus: "In Progress",
+		AssigneeChanged: true, ToAssignee: "Agent", Creator: "@user"},
+	tmplSLAAlert: SLAAlertData{Key: "KEY-1", Summary: "Title", URL: "https://example.com/browse/KEY-1", Target: "first_response", Due: time.Now()},
+	tmplTicketsDigest: TicketsDigestData{ChatTitle: "Chat", Total: 2,
+		Active: []TicketLine{{Key: "KEY-1", Name: "Title", Status: "Open"}},
+		Ready:  []TicketLine{{Key: "KEY-2", Name: "Title", Status: "Done"}}},
+	tmplMyTickets: MyTicketsData{Total: 2, NotifyPrivate: true, Chats: []MyTicketsChat{{Title: "Chat",
+		Tickets: []TicketLine{{Key: "KEY-1", Name: "Title", Status: "Open"}}}, {Tickets: []TicketLine{{Key: "KEY-2", Name: "Title", Status: "Done"}}}}},
+	tmplSearchResults: SearchResultsData{Query: "printer", Page: 2, Issues: []IssueLine{{Key: "KEY-1", Summary: "Title", Status: "Open", Assignee: "Agent",
+		URL: "https://example.com/browse/KEY-1"}}},
+	tmplIssueCard:               IssueLine{Key: "KEY-1", Summary: "Title", Status: "Open", Assignee: "Agent", Priority: "High", URL: "https://example.com/browse/KEY-1"},
+	tmplDuplicates:              DuplicatesData{Tickets: []IssueLine{{Key: "KEY-1", Summary: "Title", Status: "Open", URL: "https://example.com/browse/KEY-1"}}},
+	tmplWizardConfirm:           WizardConfirmData{Summary: "Title", Type: "Bug", Priority: "High", Component: "Backend"},
+	tmplCommentJiraToTelegram:   CommentJiraToTelegramData{Key: "KEY-1", TicketAuthor: `<a href="tg://user?id=1">User</a>`, CommentAuthor: "Agent", Text: "Text"},
+	tmplHelp:                    HelpData{HelpInfo: HelpInfo{BotUserName: "bot", ProjectKey: "KEY"}, Commands: []tgbotapi.BotCommand{{Command: "help", Description: "Help"}}},
+	tmplJiraCommentFromTelegram: JiraCommentFromTelegramData{ChatTitle: "Chat", Author: "User", Text: "Text", ReplyTo: "Reply", URL: "https://t.me/c/1/2"},
+	tmplJiraCommentReopen:       JiraCommentReopenData{User: "User", ChatTitle: "Chat"},
+	tmplDailyDigest: DailyDigestData{Date: time.Now(), Total: 1, StaleDays: 3, StaleCount: 1, Groups: []DigestGroup{{Status: "Open",
+		Tickets: []DigestTicket{{Key: "KEY-1", Name: "Title", URL: "https://example.com/browse/KEY-1", AgeDays: 5, Stale: true}}}}},
+	tmplIssueDescription: IssueDescriptionData{Title: "Title", ChatTitle: "Chat", ChatURL: "https://t.me/chat",
+		Messages: []DescriptionMessage{{Date: time.Now(), Author: "User", Text: "Text", URL: "https://t.me/chat/1"}}},
+}
+
+type renderer struct {
+	html map[Lang]*htmltemplate.Template
+	text map[Lang]*texttemplate.Template
+}
+
+var (
+	rendererMu      sync.RWMutex
+	currentRenderer *renderer
+)
+
+// LoadTemplates parses message templates for every language and checks that each of them renders.
+// A file in dir/<lang>/ overrides dir/, which overrides the embedded default. Empty dir means defaults only.
+func LoadTemplates(dir string) error {
+	r, err := newRenderer(dir)
+	if err != nil {
+		return err
+	}
+	for _, lang := range Langs {
+		for name, data := range templateSamples {
+			out, err := r.render(lang, name, data)
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(name, ".adf.tmpl") {
+				for _, line := range strings.Split(out, "\n") {
+					if line = strings.TrimSpace(line); line != "" && !json.Valid([]byte(line)) {
+						return fmt.Errorf("template %s (%s): invalid ADF block %q", name, lang, line)
+					}
+				}
+			}
+		}
+	}
+	rendererMu.Lock()
+	currentRenderer = r
+	rendererMu.Unlock()
+	return nil
+}
+
+func newRenderer(dir string) (*renderer, error) {
+	r := &renderer{
+		html: make(map[Lang]*htmltemplate.Template),
+		text: make(map[Lang]*texttemplate.Template),
+	}
+	for _, lang := range Langs {
+		h := htmltemplate.New("").Funcs(htmlFuncs(lang))
+		t := texttemplate.New("").Funcs(textFuncs(lang))
+		for name := range templateSamples {
+			src, err := readTemplate(dir, lang, name)
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasSuffix(name, ".html.tmpl") {
+				_, err = h.New(name).Parse(src)
+			} else {
+				_, err = t.New(name).Parse(src)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("template %s (%s): %w", name, lang, err)
+			}
+		}
+		r.html[lang] = h
+		r.text[lang] = t
+	}
+	return r, nil
+}
+
+func readTemplate(dir string, lang Lang, name string) (string, error) {
+	if dir != "" {
+		for _, path := range []string{filepath.Join(dir, string(lang), name), filepath.Join(dir, name)} {
+			data, err := os.ReadFile(path)
+			if err == nil {
+				return string(data), nil
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+	}
+	data, err := embeddedTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *renderer) render(lang Lang, name string, data any) (string, error) {
+	if _, ok := r.html[lang]; !ok {
+		lang = defaultLang
+	}
+	var buf bytes.Buffer
+	var err error
+	if strings.HasSuffix(name, ".html.tmpl") {
+		err = r.html[lang].ExecuteTemplate(&buf, name, data)
+	} else {
+		err = r.text[lang].ExecuteTemplate(&buf, name, data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("template %s (%s): %w", name, lang, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// render executes a template, loading embedded defaults if LoadTemplates was not called.
+// Templates are validated at startup, so errors here are only logged.
+func render(lang Lang, name string, data any) string {
+	rendererMu.RLock()
+	r := currentRenderer
+	rendererMu.RUnlock()
+	if r == nil {
+		if err := LoadTemplates(""); err != nil {
+			slog.Error("Failed to load default templates", "err", err)
+			return ""
+		}
+		return render(lang, name, data)
+	}
+	out, err := r.render(lang, name, data)
+	if err != nil {
+		slog.Error("Failed to render template", "err", err)
+	}
+	return out
+}
+
+func commonFuncs(lang Lang) map[string]any {
+	return map[string]any{
+		"plural":       func(n int, key string) string { return Plural(lang, n, key) },
+		"pluralN":      func(n int, key string) string { return PluralN(lang, n, key) },
+		"statusIcon":   func(status string) string { return GetStatusWithIcon(lang, status) },
+		"priorityIcon": GetPriorityWithIcon,
+		"date":         func(t time.Time) string { return FormatDate(lang, t) },
+		"formatTime":   func(t time.Time, layout string) string { return t.Format(layout) },
+	}
+}
+
+// htmlFuncs: catalog messages are trusted HTML, their arguments are escaped.
+func htmlFuncs(lang Lang) htmltemplate.FuncMap {
+	funcs := htmltemplate.FuncMap(commonFuncs(lang))
+	funcs["t"] = func(key string, args ...any) htmltemplate.HTML {
+		for i, a := range args {
+			if s, ok := a.(string); ok {
+				args[i] = EscapeHTML(s)
+			}
+		}
+		return htmltemplate.HTML(T(lang, key, args...))
+	}
+	return funcs
+}
+
+func textFuncs(lang Lang) texttemplate.FuncMap {
+	funcs := texttemplate.FuncMap(commonFuncs(lang))
+	funcs["t"] = func(key string, args ...any) string { return T(lang, key, args...) }
+	funcs["json"] = func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+	return funcs
+}
+
+// renderADFBlocks renders an *.adf.tmpl template: each non-empty output line is a JSON ADF block.
+func renderADFBlocks(lang Lang, name string, data any) []any {
+	blocks := []any{}
+	for _, line := range strings.Split(render(lang, name, data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var block map[string]any
+		if err := json.Unmarshal([]byte(line), &block); err != nil {
+			slog.Error("Failed to parse ADF block", "template", name, "err", err)
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
@@ -2,10 +2,13 @@ package text
 
 import (
 	"fmt"
+	"html/template"
+	"slices"
 	"strings"
 	"time"
 
 	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/sla"
 
 	"telegram-bot-jira/internal/store"
 
@@ -22,197 +25,579 @@ func BuildFullNameUser(user *tgbotapi.User) string {
 	return user.FirstName + " " + user.LastName + " (@" + user.UserName + ")"
 }
 
-func TextAnchorReplyJiraToTelegram() string {
-	return "Для ответа прикрепите это сообщение"
+func TextAnchorReplyJiraToTelegram(lang Lang) string {
+	return T(lang, "anchor.reply_jira")
 }
 
-func TextAnchorReplyStatusToJira() string {
-	return "‼️ Чтобы добавить информацию к заявке, ОБЯЗАТЕЛЬНО ответьте на это сообщение‼️"
+func TextAnchorReplyStatusToJira(lang Lang) string {
+	return T(lang, "anchor.reply_status")
+}
+
+// IsReplyAnchor проверяет, содержит ли текст якорь для ответа на любом из языков.
+func IsReplyAnchor(text string) bool {
+	for _, lang := range Langs {
+		if strings.Contains(text, TextAnchorReplyJiraToTelegram(lang)) || strings.Contains(text, TextAnchorReplyStatusToJira(lang)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasJiraReplyAnchor проверяет наличие якоря комментария из Jira на любом из языков.
+func hasJiraReplyAnchor(text string) bool {
+	for _, lang := range Langs {
+		if strings.Contains(text, TextAnchorReplyJiraToTelegram(lang)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandDescription — описание команды бота для меню и справки.
+func CommandDescription(lang Lang, command string) string {
+	return T(lang, "command."+command)
+}
+
+// ButtonReopen — подпись кнопки переоткрытия тикета.
+func ButtonReopen(lang Lang) string {
+	return T(lang, "button.reopen")
+}
+
+// ButtonRefreshStatus — подпись кнопки обновления статуса.
+func ButtonRefreshStatus(lang Lang) string {
+	return T(lang, "button.refresh_status")
+}
+
+// ButtonFollow — кнопка подписки на тикет.
+func ButtonFollow(lang Lang) string {
+	return T(lang, "button.follow")
+}
+
+// ButtonBack — кнопка возврата к предыдущему шагу мастера.
+func ButtonBack(lang Lang) string {
+	return T(lang, "button.back")
+}
+
+// ButtonCancel — кнопка отмены мастера.
+func ButtonCancel(lang Lang) string {
+	return T(lang, "button.cancel")
+}
+
+// ButtonCreate — кнопка создания тикета в мастере.
+func ButtonCreate(lang Lang) string {
+	return T(lang, "button.create")
+}
+
+// ButtonMergeInto — кнопка добавления обращения комментарием в похожий тикет.
+func ButtonMergeInto(lang Lang, issueKey string) string {
+	return T(lang, "button.merge", issueKey)
+}
+
+// ButtonCreateAnyway — кнопка создания тикета несмотря на похожие.
+func ButtonCreateAnyway(lang Lang) string {
+	return T(lang, "button.create_anyway")
+}
+
+// ButtonPrev — кнопка предыдущей страницы результатов.
+func ButtonPrev(lang Lang) string {
+	return T(lang, "button.prev")
+}
+
+// ButtonNext — кнопка следующей страницы результатов.
+func ButtonNext(lang Lang) string {
+	return T(lang, "button.next")
 }
 
 // ------------------ TELEGRAM ------------------
 
 // TextErrorCreateTicket возвращает человеко-понятное описание ошибки создания тикета.
-func TextErrorCreateTicket(err error) string {
-	return "Не удалось создать тикет"
+func TextErrorCreateTicket(lang Lang, err error) string {
+	return T(lang, "error.create_ticket")
 }
 
 // TextErrorCreateTicket возвращает человеко-понятное описание ошибки создания тикета.
-func TextErrorCreateTicketDebug(err error) string {
-	msg := "неизвестная ошибка"
+func TextErrorCreateTicketDebug(lang Lang, err error) string {
+	msg := T(lang, "error.unknown")
 	if err != nil {
 		msg = err.Error()
 	}
-	return fmt.Sprintf("Не удалось создать тикет.\n\nДетали:\n`%s`", msg)
+	return T(lang, "error.create_ticket_debug", msg)
+}
+
+// TextReopenFailed — ошибка переоткрытия тикета.
+func TextReopenFailed(lang Lang, key string, err error) string {
+	return T(lang, "error.reopen_failed", key, err)
+}
+
+// TextGetStatusFailed — ошибка получения информации по тикету.
+func TextGetStatusFailed(lang Lang, key string, err error) string {
+	return T(lang, "error.get_status_failed", key, err)
 }
 
 // TextTicketCreatedHTML сообщение о создании тикета (HTML).
-func TextTicketCreatedHTML(title, issueKey, url string) string {
-	return fmt.Sprintf(
-		"🎉 <b>Задача успешно создана</b>\n\n"+
-			"📚 <b>Название:</b> <code>%s</code>\n"+
-			"🗝️ <b>Ключ:</b> <code>%s</code>\n"+
-			"🔗 <b>Ссылка:</b> <a href=\"%s\">%s</a>",
-		EscapeHTML(title),
-		EscapeHTML(issueKey),
-		url,
-		EscapeHTML(url),
-	)
-}
-
-// BuildUserMentionHTML формирует HTML-упоминание пользователя.
-func BuildUserMentionHTML(userID int64, username, displayName string) string {
-	if strings.TrimSpace(username) != "" {
+func TextTicketCreatedHTML(lang Lang, title, issueKey, url string) string {
+	return render(lang, tmplTicketCreated, TicketCreatedData{Title: title, Key: issueKey, URL: url})
+}
+
+// BuildUserMentionHTML формирует HTML-упоминание пользователя: по ID, если он известен
+// (работает без username и после его смены), иначе по @username.
+func BuildUserMentionHTML(lang Lang, userID int64, username, displayName string) string {
+	username = strings.TrimSpace(username)
+	name := strings.TrimSpace(displayName)
+	if userID == 0 && username != "" {
 		return "@" + EscapeHTML(username)
 	}
-	name := strings.TrimSpace(displayName)
+	if name == "" && username != "" {
+		name = "@" + username
+	}
 	if name == "" {
-		name = "пользователь"
+		name = T(lang, "user.fallback")
+	}
+	if userID == 0 {
+		return EscapeHTML(name)
 	}
 	return fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", userID, EscapeHTML(name))
 }
 
-// TextTicketClosedHTML сообщение о закрытии тикета (HTML) с упоминанием автора.
-func TextTicketClosedHTML(key, status, url, userCreator string) string {
-	return fmt.Sprintf(
-		"✅ <b>Тикет закрыт</b>\n\n"+
-			"🗝️ <b>Ключ:</b> <code>%s</code>\n"+
-			"📌 <b>Статус:</b> %s\n"+
-			"🔗 <b>Ссылка:</b> <a href=\"%s\">%s</a>\n\n"+
-			"@%s, тикет закрыт.",
-		EscapeHTML(key),
-		EscapeHTML(status),
-		url,
-		EscapeHTML(url),
-		userCreator,
-	)
+// MentionHTML — упоминание пользователя Telegram; пусто, если о нём ничего не известно.
+func MentionHTML(lang Lang, user store.TelegramUser) string {
+	if user.IsZero() {
+		return ""
+	}
+	return BuildUserMentionHTML(lang, user.ID, user.Username, user.Name)
+}
+
+// MentionsHTML — упоминания пользователей через запятую, без пустых и повторов.
+func MentionsHTML(lang Lang, users []store.TelegramUser) string {
+	var mentions []string
+	for i, user := range users {
+		if user.IsZero() || slices.ContainsFunc(users[:i], func(u store.TelegramUser) bool { return u.Is(user.ID, user.Username) }) {
+			continue
+		}
+		mentions = append(mentions, MentionHTML(lang, user))
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// TextTicketTopicName — название темы форума, создаваемой для тикета.
+func TextTicketTopicName(lang Lang, key, name string) string {
+	return T(lang, "ticket.topic_name", key, name)
+}
+
+// TextTicketTopicCreatedHTML — сообщение со ссылкой на созданную для тикета тему форума.
+func TextTicketTopicCreatedHTML(lang Lang, key, link string) string {
+	if link == "" {
+		return T(lang, "ticket.topic_created", EscapeHTML(key))
+	}
+	return T(lang, "ticket.topic_created_link", EscapeHTML(key), EscapeHTML(link))
+}
+
+// TextTicketClosedHTML сообщение о закрытии тикета (HTML) с упоминанием автора и подписчиков.
+func TextTicketClosedHTML(lang Lang, key, status, url string, mentions []store.TelegramUser) string {
+	return render(lang, tmplTicketClosed, TicketClosedData{Key: key, Status: status, URL: url, Creator: template.HTML(MentionsHTML(lang, mentions))})
+}
+
+// TextTicketChangedHTML — уведомление о смене статуса или ответственного (HTML).
+func TextTicketChangedHTML(lang Lang, data TicketChangedData, mentions []store.TelegramUser) string {
+	data.Creator = template.HTML(MentionsHTML(lang, mentions))
+	return render(lang, tmplTicketChanged, data)
 }
 
 // TextGetStatus выводит краткую информацию о тикете (HTML).
-func TextGetStatus(issue *jira.IssueStatus, summary, author string) string {
+func TextGetStatus(lang Lang, issue *jira.IssueStatus, summary string, author store.TelegramUser, slaStatus *sla.Status) string {
+	return TextTicketCardHTML(lang, NewTicketStatusData(lang, issue, summary, author, slaStatus))
+}
+
+// NewTicketStatusData собирает данные карточки тикета из задачи Jira.
+func NewTicketStatusData(lang Lang, issue *jira.IssueStatus, summary string, author store.TelegramUser, slaStatus *sla.Status) TicketStatusData {
 	if summary == "" {
 		summary = issue.Summary
 	}
-	status := issue.Status
-	assignee := issue.Assignee
-	if strings.TrimSpace(assignee) == "" {
-		assignee = "Не назначен"
+	return TicketStatusData{
+		Key:      issue.Key,
+		Summary:  summary,
+		Status:   issue.Status,
+		Assignee: strings.TrimSpace(issue.Assignee),
+		Priority: issue.Priority,
+		Created:  issue.Created,
+		Updated:  issue.Updated,
+		Author:   template.HTML(MentionHTML(lang, author)),
+		SLA:      slaStatus,
 	}
-	created := issue.Created
-	updated := issue.Updated
-
-	return fmt.Sprintf(
-		"📚 <b>Название:</b> <code>%s</code>\n"+
-			"🗝️ <b>Ключ:</b> <code>%s</code>\n\n"+
-			"📌 <b>Статус:</b> %s\n"+
-			"👤 <b>Ответственный:</b> %s\n\n"+
-			"🕑 <b>Создан:</b> %s\n"+
-			"♻️ <b>Обновлён:</b> %s\n\n"+
-			"✍️ <b>Автор:</b> @%s\n\n"+
-			"<b>%s</b>",
-		EscapeHTML(summary),
-		EscapeHTML(issue.Key),
-		GetStatusWithIcon(status),
-		EscapeHTML(assignee),
-		FormatDate(created),
-		FormatDate(updated),
-		author,
-		TextAnchorReplyStatusToJira(),
-	)
-}
-
-func TextTelegramTicketsMessage(tickets []store.CreatedTicket, chatTitle string) string {
-	var b strings.Builder
-	b.WriteString("🗂 <b>Тикеты этого чата</b>")
-
-	b.WriteString("\n\n")
-
-	if len(tickets) == 0 {
-		b.WriteString("В этом чате ещё не создано ни одного тикета.\n")
-	} else {
-		activeTickets := make([]store.CreatedTicket, 0, len(tickets))
-		readyTickets := make([]store.CreatedTicket, 0)
-		for _, ticket := range tickets {
-			if IsReadyStatus(ticket.Status) {
-				readyTickets = append(readyTickets, ticket)
-				continue
-			}
-			activeTickets = append(activeTickets, ticket)
-		}
+}
 
-		writeTicketLine := func(ticket store.CreatedTicket) {
-			name := strings.TrimSpace(ticket.Name)
-			if name == "" {
-				name = TextTitleIssue("")
-			}
-			b.WriteString(fmt.Sprintf(
-				"• <code>%s</code> — %s — %s\n",
-				EscapeHTML(ticket.Key),
-				EscapeHTML(name),
-				EscapeHTML(GetStatusWithIcon(ticket.Status)),
-			))
-		}
+// TextTicketCardHTML — карточка тикета (HTML), в том числе живая с лентой событий.
+func TextTicketCardHTML(lang Lang, data TicketStatusData) string {
+	return render(lang, tmplTicketStatus, data)
+}
 
-		for _, ticket := range activeTickets {
-			writeTicketLine(ticket)
+// TextTelegramTicketsMessage — список тикетов чата (HTML).
+func TextTelegramTicketsMessage(lang Lang, tickets []store.CreatedTicket, chatTitle string) string {
+	data := TicketsDigestData{ChatTitle: chatTitle, Total: len(tickets)}
+	for _, ticket := range tickets {
+		name := strings.TrimSpace(ticket.Name)
+		if name == "" {
+			name = TextTitleIssue(lang, "")
 		}
-
-		if len(readyTickets) > 0 {
-			if len(activeTickets) > 0 {
-				b.WriteString("\n")
-			}
-			b.WriteString("<b>Тикеты в статусе «Готов»</b>\n")
-			for _, ticket := range readyTickets {
-				writeTicketLine(ticket)
-			}
+		line := TicketLine{Key: ticket.Key, Name: name, Status: ticket.Status}
+		if IsReadyStatus(ticket.Status) {
+			data.Ready = append(data.Ready, line)
+		} else {
+			data.Active = append(data.Active, line)
 		}
 	}
+	return render(lang, tmplTicketsDigest, data)
+}
+
+// TextMyTicketsHTML — тикеты автора по всем чатам (HTML).
+func TextMyTicketsHTML(lang Lang, data MyTicketsData) string {
+	return render(lang, tmplMyTickets, data)
+}
+
+// TextNotifyPrivate — ответ на /notify_private: куда теперь приходят уведомления.
+func TextNotifyPrivate(lang Lang, enabled bool) string {
+	if enabled {
+		return T(lang, "notify_private.on")
+	}
+	return T(lang, "notify_private.off")
+}
+
+// TextWatchUsage — подсказка по /watch и /unwatch.
+func TextWatchUsage(lang Lang) string {
+	return T(lang, "watch.usage")
+}
+
+// TextWatchState — результат подписки на тикет или отписки от него.
+func TextWatchState(lang Lang, issueKey string, watching bool) string {
+	if watching {
+		return T(lang, "watch.on", EscapeHTML(issueKey))
+	}
+	return T(lang, "watch.off", EscapeHTML(issueKey))
+}
+
+// TextWatchToast — короткое уведомление о подписке для кнопки (без разметки).
+func TextWatchToast(lang Lang, issueKey string, watching bool) string {
+	if watching {
+		return T(lang, "watch.toast_on", issueKey)
+	}
+	return T(lang, "watch.toast_off", issueKey)
+}
+
+// TextWatchFailed — не удалось изменить подписку (например, наблюдателей в Jira).
+func TextWatchFailed(lang Lang, issueKey string, err error) string {
+	return T(lang, "watch.failed", issueKey, err)
+}
+
+// TextJiraLinkSubject — тема письма Jira с кодом подтверждения /link_jira.
+func TextJiraLinkSubject(lang Lang) string {
+	return T(lang, "jira.link_subject")
+}
+
+// TextJiraLinkBody — текст письма Jira с кодом подтверждения /link_jira.
+func TextJiraLinkBody(lang Lang, telegramUser, code string) string {
+	return T(lang, "jira.link_body", telegramUser, code)
+}
+
+// TextLinkUsage — подсказка по /link_jira.
+func TextLinkUsage(lang Lang) string {
+	return T(lang, "link.usage")
+}
+
+// TextLinkStatus — к какому аккаунту Jira привязан пользователь.
+func TextLinkStatus(lang Lang, name, email string) string {
+	if email == "" {
+		return T(lang, "link.status", EscapeHTML(name))
+	}
+	return T(lang, "link.status_email", EscapeHTML(name), EscapeHTML(email))
+}
+
+// TextLinkCodeSent — код подтверждения отправлен на почту аккаунта Jira.
+func TextLinkCodeSent(lang Lang, email string) string {
+	return T(lang, "link.code_sent", EscapeHTML(email))
+}
+
+// TextLinkNotConfigured — не задана задача для отправки кодов подтверждения.
+func TextLinkNotConfigured(lang Lang) string {
+	return T(lang, "link.not_configured")
+}
+
+// TextLinkUserNotFound — почте не соответствует ровно один аккаунт Jira.
+func TextLinkUserNotFound(lang Lang) string {
+	return T(lang, "link.user_not_found")
+}
+
+// TextLinkTooSoon — код запрашивали недавно или было слишком много неверных кодов.
+func TextLinkTooSoon(lang Lang) string {
+	return T(lang, "link.too_soon")
+}
+
+// TextLinkFailed — не удалось отправить код подтверждения.
+func TextLinkFailed(lang Lang, err error) string {
+	return T(lang, "link.failed", EscapeHTML(err.Error()))
+}
+
+// TextLinkConfirmed — аккаунт Jira привязан.
+func TextLinkConfirmed(lang Lang, name string) string {
+	return T(lang, "link.confirmed", EscapeHTML(name))
+}
+
+// TextLinkBadCode — неверный или просроченный код.
+func TextLinkBadCode(lang Lang) string {
+	return T(lang, "link.bad_code")
+}
+
+// TextUnlink — результат /unlink_jira.
+func TextUnlink(lang Lang, unlinked bool) string {
+	if unlinked {
+		return T(lang, "link.unlinked")
+	}
+	return T(lang, "link.not_linked")
+}
+
+// TextAssignUsage — подсказка по /assign.
+func TextAssignUsage(lang Lang) string {
+	return T(lang, "assign.usage")
+}
+
+// TextAssigned — тикет назначен на пользователя (HTML).
+func TextAssigned(lang Lang, issueKey string, user store.TelegramUser) string {
+	return T(lang, "assign.done", EscapeHTML(issueKey), MentionHTML(lang, user))
+}
+
+// TextAssigneeNotLinked — у пользователя нет привязанного аккаунта Jira (HTML).
+func TextAssigneeNotLinked(lang Lang, user store.TelegramUser) string {
+	return T(lang, "assign.not_linked", MentionHTML(lang, user))
+}
+
+// TextAssignFailed — ошибка назначения тикета.
+func TextAssignFailed(lang Lang, issueKey string, err error) string {
+	return T(lang, "assign.failed", issueKey, err)
+}
+
+// TextWizardStep — вопрос шага мастера создания тикета: summary, type, priority или component.
+func TextWizardStep(lang Lang, step string, n, total int) string {
+	return T(lang, "wizard."+step, n, total)
+}
+
+// TextWizardPlaceholder — подсказка в поле ввода для шага summary.
+func TextWizardPlaceholder(lang Lang) string {
+	return T(lang, "wizard.placeholder")
+}
+
+// TextWizardConfirmHTML — последний шаг мастера: выбранные значения тикета.
+func TextWizardConfirmHTML(lang Lang, data WizardConfirmData) string {
+	return render(lang, tmplWizardConfirm, data)
+}
+
+// TextWizardCancelled — мастер создания тикета отменён.
+func TextWizardCancelled(lang Lang) string {
+	return T(lang, "wizard.cancelled")
+}
+
+// TextWizardNotYours — кнопку мастера нажал не его автор (без разметки).
+func TextWizardNotYours(lang Lang) string {
+	return T(lang, "wizard.not_yours")
+}
+
+// TextWizardExpired — мастер уже завершён или устарел (без разметки).
+func TextWizardExpired(lang Lang) string {
+	return T(lang, "wizard.expired")
+}
+
+// TextDuplicatesHTML — похожие открытые тикеты перед созданием нового.
+func TextDuplicatesHTML(lang Lang, tickets []IssueLine) string {
+	return render(lang, tmplDuplicates, DuplicatesData{Tickets: tickets})
+}
+
+// TextMergedHTML — обращение добавлено комментарием в существующий тикет.
+func TextMergedHTML(lang Lang, issueKey, url string) string {
+	return T(lang, "duplicate.merged", EscapeHTML(url), EscapeHTML(issueKey))
+}
+
+// TextMergeFailed — не удалось добавить обращение в существующий тикет.
+func TextMergeFailed(lang Lang, issueKey string, err error) string {
+	return T(lang, "duplicate.merge_failed", issueKey, err)
+}
+
+// TextTrackUsage — подсказка по /track.
+func TextTrackUsage(lang Lang) string {
+	return T(lang, "track.usage")
+}
+
+// TextTrackOtherProject — задача из другого проекта Jira.
+func TextTrackOtherProject(lang Lang, issueKey, projectKey string) string {
+	return T(lang, "track.other_project", EscapeHTML(issueKey), EscapeHTML(projectKey))
+}
+
+// TextTrackAlready — тикет уже отслеживается в этом или другом чате.
+func TextTrackAlready(lang Lang, issueKey string, here bool) string {
+	if here {
+		return T(lang, "track.already_here", EscapeHTML(issueKey))
+	}
+	return T(lang, "track.already_other", EscapeHTML(issueKey))
+}
+
+// TextTrackNotLinked — для /track нужен привязанный аккаунт Jira.
+func TextTrackNotLinked(lang Lang) string {
+	return T(lang, "track.not_linked")
+}
+
+// TextTrackForbidden — у аккаунта Jira нет доступа к задаче.
+func TextTrackForbidden(lang Lang, issueKey string) string {
+	return T(lang, "track.forbidden", EscapeHTML(issueKey))
+}
+
+// TextTrackFailed — ошибка проверки доступа к задаче.
+func TextTrackFailed(lang Lang, issueKey string, err error) string {
+	return T(lang, "track.failed", issueKey, err)
+}
+
+// TextTracked — задача подключена к чату.
+func TextTracked(lang Lang, issueKey string) string {
+	return T(lang, "track.done", EscapeHTML(issueKey))
+}
+
+// TextUntrackUsage — подсказка по /untrack.
+func TextUntrackUsage(lang Lang) string {
+	return T(lang, "untrack.usage")
+}
+
+// TextNotTracked — задача не подключалась к чату через /track.
+func TextNotTracked(lang Lang, issueKey string) string {
+	return T(lang, "untrack.not_tracked", EscapeHTML(issueKey))
+}
+
+// TextUntracked — задача отключена от чата.
+func TextUntracked(lang Lang, issueKey string) string {
+	return T(lang, "untrack.done", EscapeHTML(issueKey))
+}
+
+// TextSearchUsage — подсказка по /search.
+func TextSearchUsage(lang Lang) string {
+	return T(lang, "search.usage")
+}
+
+// TextSearchResultsHTML — страница результатов /search; page начинается с 1.
+func TextSearchResultsHTML(lang Lang, query string, page int, issues []IssueLine) string {
+	return render(lang, tmplSearchResults, SearchResultsData{Query: query, Page: page, Issues: issues})
+}
+
+// TextSearchEmpty — по запросу ничего не найдено (HTML).
+func TextSearchEmpty(lang Lang, query string) string {
+	return T(lang, "search.empty", EscapeHTML(query))
+}
+
+// TextSearchFailed — ошибка поиска в Jira.
+func TextSearchFailed(lang Lang, err error) string {
+	return T(lang, "search.failed", err)
+}
 
-	b.WriteString("\nОтправьте <code>/status_issue TEC-123</code>, чтобы посмотреть детали конкретного тикета.")
-	return b.String()
+// TextSearchNeedLink — поиск в личном чате доступен только с привязанным аккаунтом Jira (без разметки).
+func TextSearchNeedLink(lang Lang) string {
+	return T(lang, "search.need_link")
+}
+
+// TextSearchExpired — результаты поиска устарели (без разметки).
+func TextSearchExpired(lang Lang) string {
+	return T(lang, "search.expired")
+}
+
+// TextIssueCardHTML — карточка задачи, отправляемая из инлайн-режима.
+func TextIssueCardHTML(lang Lang, issue IssueLine) string {
+	return render(lang, tmplIssueCard, issue)
+}
+
+// TextInlineDescription — строка под заголовком результата инлайн-режима: статус и исполнитель.
+func TextInlineDescription(lang Lang, issue IssueLine) string {
+	assignee := issue.Assignee
+	if assignee == "" {
+		assignee = T(lang, "assignee.none")
+	}
+	return T(lang, "search.inline_desc", GetStatusWithIcon(lang, issue.Status), assignee)
+}
+
+// TextTicketNotYours — тикет создан другим пользователем (личка с ботом).
+func TextTicketNotYours(lang Lang, issueKey string) string {
+	return T(lang, "ticket.not_yours", EscapeHTML(issueKey))
+}
+
+// TextDailyDigestHTML — ежедневный дайджест открытых тикетов чата (HTML).
+func TextDailyDigestHTML(lang Lang, data DailyDigestData) string {
+	return render(lang, tmplDailyDigest, data)
+}
+
+// TextSLAAlertHTML — предупреждение о приближении или нарушении срока SLA (HTML).
+func TextSLAAlertHTML(lang Lang, data SLAAlertData) string {
+	return render(lang, tmplSLAAlert, data)
 }
 
 // TextGetStatusNotFound — если тикет не найден.
-func TextGetStatusNotFound(issueKey string) string {
-	return fmt.Sprintf("Тикет <code>%s</code> не найден", issueKey)
+func TextGetStatusNotFound(lang Lang, issueKey string) string {
+	return T(lang, "ticket.not_found", issueKey)
+}
+
+// TextTicketNotFromBot — тикет создан не через бота.
+func TextTicketNotFromBot(lang Lang, issueKey string) string {
+	return T(lang, "ticket.not_from_bot", EscapeHTML(issueKey), EscapeHTML(issueKey))
+}
+
+// TextTicketTooOldToReopen — тикет уже удалён из хранилища и не может быть переоткрыт.
+func TextTicketTooOldToReopen(lang Lang, issueKey string) string {
+	return T(lang, "ticket.too_old_reopen", EscapeHTML(issueKey))
 }
 
 // TextTitleIssue — заголовок тикета по названию чата.
-func TextTitleIssue(chatTitle string) string {
+func TextTitleIssue(lang Lang, chatTitle string) string {
 	if chatTitle != "" {
-		return "Обращение из Telegram \"" + chatTitle + "\""
+		return T(lang, "ticket.title_chat", chatTitle)
 	}
-	return "Обращение из Telegram"
+	return T(lang, "ticket.title")
 }
 
-func TextCommentJiraToTelegram(key, ticketAuthor, commentAuthor, text string) string {
-	return fmt.Sprintf("📬 Комментарий по <code>%s</code>\n"+
-		"👤 от %s для @%s\n\n"+
-		"💬 <b>%s</b>\n\n"+
-		"📣 %s",
-		EscapeHTML(key),
-		EscapeHTML(commentAuthor),
-		EscapeHTML(ticketAuthor),
-		EscapeHTML(text),
-		TextAnchorReplyJiraToTelegram(),
-	)
+func TextCommentJiraToTelegram(lang Lang, key string, mentions []store.TelegramUser, commentAuthor, text string) string {
+	return render(lang, tmplCommentJiraToTelegram, CommentJiraToTelegramData{
+		Key:           key,
+		TicketAuthor:  template.HTML(MentionsHTML(lang, mentions)),
+		CommentAuthor: commentAuthor,
+		Text:          text,
+	})
 }
 
-// ------------------ JIRA ------------------
+// HelpInfo — настройки бота и чата, которые попадают в справку.
+type HelpInfo struct {
+	BotUserName   string
+	ProjectKey    string
+	ReopenEnabled bool
+	ErrorChat     bool
+	Private       bool
+}
 
-// TextJiraCommentReopen — текст комментария о переоткрытии в Jira.
-func TextJiraCommentReopen(userName, chatTitle string) string {
+// TextHelpHTML — справка по работе с ботом (HTML), собранная из реестра команд и настроек чата.
+func TextHelpHTML(lang Lang, commands []tgbotapi.BotCommand, info HelpInfo) string {
+	return render(lang, tmplHelp, HelpData{HelpInfo: info, Commands: commands})
+}
+
+// TextBotAddedHTML — приветствие при добавлении бота в новую группу.
+func TextBotAddedHTML(lang Lang, chatTitle string) string {
 	if chatTitle != "" {
-		return fmt.Sprintf("👤 Пользователь: %s в чате %s\nЗапросил переоткрытие.", userName, chatTitle)
+		return T(lang, "bot.added_chat", EscapeHTML(chatTitle))
 	}
-	return fmt.Sprintf("👤 Пользователь: %s запросил переоткрытие.", userName)
+	return T(lang, "bot.added")
+}
+
+// ------------------ JIRA ------------------
 
+// TextJiraCommentReopen — текст комментария о переоткрытии в Jira.
+func TextJiraCommentReopen(lang Lang, userName, chatTitle string) string {
+	return render(lang, tmplJiraCommentReopen, JiraCommentReopenData{User: userName, ChatTitle: chatTitle})
 }
 
-func TextJiraCommentUserFromTelegram(text string, user *tgbotapi.User, chatTitle, replyText string) string {
+// TextJiraCommentUserFromTelegram — комментарий в Jira из сообщения Telegram; author — имя или упоминание
+// автора в Jira, messageURL — ссылка t.me на сообщение.
+func TextJiraCommentUserFromTelegram(lang Lang, text, author, chatTitle, replyText, messageURL string) string {
 	replyClean := ""
 	// replyStatus := false
-	if strings.Contains(replyText, TextAnchorReplyJiraToTelegram()) {
+	if hasJiraReplyAnchor(replyText) {
 		// replyStatus = true
 		if replyText != "" {
 			lines := strings.Split(replyText, "\n")
@@ -224,93 +609,71 @@ func TextJiraCommentUserFromTelegram(text string, user *tgbotapi.User, chatTitle
 		}
 	}
 
-	var b strings.Builder
-	b.WriteString("💬 Сообщение из Telegram")
-	if chatTitle != "" {
-		b.WriteString(" (" + chatTitle + ")\n")
-	} else {
-		b.WriteString("\n")
-	}
-	b.WriteString(fmt.Sprintf("👤 Автор: %s\n", BuildFullNameUser(user)))
-	b.WriteString(text)
+	return render(lang, tmplJiraCommentFromTelegram, JiraCommentFromTelegramData{
+		ChatTitle: chatTitle,
+		Author:    author,
+		Text:      text,
+		ReplyTo:   replyClean,
+		URL:       messageURL,
+	})
+}
 
-	if replyClean != "" {
-		b.WriteString("\n\n🔁 Ответ на: ")
-		b.WriteString(replyClean)
-	}
+// TextRemoteLinkTitle — название ссылки на чат Telegram в разделе «Ссылки» задачи Jira.
+func TextRemoteLinkTitle(lang Lang, chatTitle string) string {
+	return T(lang, "jira.remote_link", chatTitle)
+}
 
-	return b.String()
+// TextJiraAttachment — подпись перед ссылкой на вложенный файл в комментарии Jira.
+func TextJiraAttachment(lang Lang) string {
+	return T(lang, "jira.attachment")
 }
 
-// TextDescriptionADF собирает ADF-документ Jira для описания задачи на основе истории чата.
-func TextDescriptionADF(titleIssue string, historyMessages []tgbotapi.Message, urlChat string) map[string]any {
-	doc := map[string]any{
-		"type":    "doc",
-		"version": 1,
-		"content": []any{},
-	}
-	appendBlock := func(b any) { doc["content"] = append(doc["content"].([]any), b) }
+// TextAggregateHeading — заголовок описания агрегирующей задачи.
+func TextAggregateHeading(lang Lang) string {
+	return T(lang, "jira.aggregate_heading")
+}
 
-	if titleIssue != "" {
-		appendBlock(map[string]any{
-			"type":    "heading",
-			"attrs":   map[string]any{"level": 3},
-			"content": []any{map[string]any{"type": "text", "text": "Тема: " + titleIssue}},
-		})
-	}
+// TextAggregateNote — пояснение под заголовком агрегирующей задачи: таблица лишь представление данных из свойства.
+func TextAggregateNote(lang Lang, property string) string {
+	return T(lang, "jira.aggregate_note", property)
+}
 
-	if len(historyMessages) == 0 {
-		appendBlock(map[string]any{
-			"type":    "paragraph",
-			"content": []any{map[string]any{"type": "text", "text": "История сообщений пуста"}},
-		})
-		return doc
+// TextAggregateHeader — заголовки колонок таблицы агрегирующей задачи.
+func TextAggregateHeader(lang Lang) []string {
+	return []string{
+		T(lang, "jira.aggregate_key"),
+		T(lang, "jira.aggregate_status"),
+		T(lang, "jira.aggregate_name"),
+		T(lang, "jira.aggregate_chat"),
+		T(lang, "jira.aggregate_author"),
+		T(lang, "jira.aggregate_comment"),
+		T(lang, "jira.aggregate_created"),
+		T(lang, "jira.aggregate_first_response"),
+		T(lang, "jira.aggregate_resolved"),
+		T(lang, "jira.aggregate_sla"),
 	}
+}
 
-	chat := historyMessages[0].Chat
-	chatTitle := strings.TrimSpace(chat.Title)
-	headingText := "Чат из переписки в Telegram"
-	if chatTitle != "" {
-		headingText += ": " + chatTitle
-	}
-	appendBlock(map[string]any{
-		"type":    "heading",
-		"attrs":   map[string]any{"level": 3},
-		"content": []any{map[string]any{"type": "text", "text": headingText}},
-	})
-	if strings.TrimSpace(urlChat) != "" && chatTitle != "" {
-		appendBlock(map[string]any{
-			"type": "paragraph",
-			"content": []any{
-				map[string]any{
-					"type":  "text",
-					"text":  chatTitle,
-					"marks": []any{map[string]any{"type": "link", "attrs": map[string]any{"href": urlChat}}},
-				},
-			},
-		})
+// TextDescriptionADF собирает ADF-документ Jira для описания задачи на основе истории чата.
+func TextDescriptionADF(lang Lang, titleIssue string, historyMessages []tgbotapi.Message, urlChat string) map[string]any {
+	data := IssueDescriptionData{Title: titleIssue, ChatURL: strings.TrimSpace(urlChat)}
+	if len(historyMessages) > 0 && historyMessages[0].Chat != nil {
+		data.ChatTitle = strings.TrimSpace(historyMessages[0].Chat.Title)
 	}
-
 	for _, m := range historyMessages {
 		if m.Text == "" {
 			continue
 		}
-		ts := int64(m.Date)
-		dateTime := time.Unix(ts, 0).In(time.Local).Format("02.01.06 15:04")
-		user := BuildFullNameUser(m.From)
-		appendBlock(map[string]any{
-			"type":    "paragraph",
-			"content": []any{map[string]any{"type": "text", "text": dateTime + " — " + user + ":", "marks": []any{map[string]any{"type": "strong"}}}},
-		})
-		appendBlock(map[string]any{
-			"type":  "panel",
-			"attrs": map[string]any{"panelType": "info"},
-			"content": []any{map[string]any{
-				"type":    "paragraph",
-				"content": []any{map[string]any{"type": "text", "text": m.Text}},
-			}},
+		data.Messages = append(data.Messages, DescriptionMessage{
+			Date:   time.Unix(int64(m.Date), 0),
+			Author: BuildFullNameUser(m.From),
+			Text:   m.Text,
+			URL:    MessageLink(m.Chat, m.MessageID),
 		})
 	}
-	appendBlock(map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "Сформировано автоматически из переписки Telegram", "marks": []any{map[string]any{"type": "em"}}}}})
-	return doc
+	return map[string]any{
+		"type":    "doc",
+		"version": 1,
+		"content": renderADFBlocks(lang, tmplIssueDescription, data),
+	}
 }
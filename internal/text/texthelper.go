@@ -3,8 +3,11 @@ package text
 import (
 	"fmt"
 	"html"
+	"strconv"
 	"strings"
 	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func EscapeMarkdownV2(text string) string {
@@ -34,9 +37,9 @@ func IsReadyStatus(status string) bool {
 	}
 }
 
-func GetStatusWithIcon(statusName string) string {
+func GetStatusWithIcon(lang Lang, statusName string) string {
 	if statusName == "" {
-		return "Неизвестно"
+		return T(lang, "status.unknown")
 	}
 	switch strings.ToLower(statusName) {
 	case "open", "открыт", "новая", "открыть", "to do", "к выполнению", "открыто повторно":
@@ -71,9 +74,9 @@ func GetPriorityWithIcon(priority string) string {
 	}
 }
 
-func FormatDate(date time.Time) string {
+func FormatDate(lang Lang, date time.Time) string {
 	if date.IsZero() {
-		return "Не указана"
+		return T(lang, "date.unknown")
 	}
 	return date.Format("02.01.2006 15:04")
 }
@@ -81,3 +84,29 @@ func FormatDate(date time.Time) string {
 func EscapeHTML(text string) string {
 	return html.EscapeString(text)
 }
+
+// MessageLink возвращает ссылку t.me на сообщение: t.me/<username>/<id> для публичных чатов,
+// t.me/c/<chat>/<id> для супергрупп и каналов; для обычных групп и личек ссылок нет — пустая строка.
+func MessageLink(chat *tgbotapi.Chat, messageID int) string {
+	if chat == nil || messageID == 0 {
+		return ""
+	}
+	if chat.UserName != "" && !chat.IsPrivate() {
+		return fmt.Sprintf("https://t.me/%s/%d", chat.UserName, messageID)
+	}
+	if chat.IsSuperGroup() || chat.IsChannel() {
+		if id, ok := strings.CutPrefix(strconv.FormatInt(chat.ID, 10), "-100"); ok {
+			return fmt.Sprintf("https://t.me/c/%s/%d", id, messageID)
+		}
+	}
+	return ""
+}
+
+// ChatLink возвращает ссылку на чат: t.me/<username> для публичных чатов, иначе ссылку на сообщение
+// messageID внутри чата (у приватных супергрупп нет собственной ссылки).
+func ChatLink(chat *tgbotapi.Chat, messageID int) string {
+	if chat != nil && chat.UserName != "" && !chat.IsPrivate() {
+		return "https://t.me/" + chat.UserName
+	}
+	return MessageLink(chat, messageID)
+}
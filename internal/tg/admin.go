@@ -0,0 +1,134 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"telegram-bot-jira/internal/store"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// notifications remembers the last Telegram notification per ticket so it can be re-sent.
+type notifications struct {
+	mu    sync.Mutex
+	byKey map[string]sentNotification
+}
+
+type sentNotification struct {
+	msg      tgbotapi.MessageConfig
+	threadID int
+}
+
+// sendTicketNotification sends a notification into the ticket's chat topic and remembers it as the last one.
+// Notifications reply to the ticket message; if it was deleted, they are sent as plain messages.
+// If the creator opted in, they go to the private chat, falling back to the group on failure.
+func (b *Bot) sendTicketNotification(ticket *CreatedTicket, msg tgbotapi.MessageConfig) error {
+	if chatID := b.privateChatID(ticket); chatID != 0 {
+		private, err := b.sendPrivateNotification(chatID, msg)
+		if err == nil {
+			b.rememberNotification(ticket.Key, private, 0)
+			return nil
+		}
+		b.log.Warn("Failed to send private notification, sending to chat", "key", ticket.Key, "chat", chatID, "error", err)
+	}
+	if ticket.MessageID != 0 {
+		msg.ReplyToMessageID = ticket.MessageID
+	}
+	_, err := sendMessage(b.api, msg, ticket.ThreadID)
+	if err != nil && msg.ReplyToMessageID != 0 && isReplyNotFound(err) {
+		b.log.Info("Ticket message is gone, sending without reply", "key", ticket.Key, "message_id", ticket.MessageID)
+		b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+			t.MessageID = 0
+			return true
+		})
+		msg.ReplyToMessageID = 0
+		_, err = sendMessage(b.api, msg, ticket.ThreadID)
+	}
+	if err != nil {
+		return err
+	}
+	b.rememberNotification(ticket.Key, msg, ticket.ThreadID)
+	return nil
+}
+
+func (b *Bot) rememberNotification(key string, msg tgbotapi.MessageConfig, threadID int) {
+	b.notifications.mu.Lock()
+	if b.notifications.byKey == nil {
+		b.notifications.byKey = make(map[string]sentNotification)
+	}
+	b.notifications.byKey[key] = sentNotification{msg: msg, threadID: threadID}
+	b.notifications.mu.Unlock()
+}
+
+func isReplyNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "message to be replied not found") ||
+		strings.Contains(strings.ToLower(err.Error()), "reply message not found")
+}
+
+// Tickets implements admin.Backend.
+func (b *Bot) Tickets() []store.CreatedTicket {
+	return b.ticketStore.ListAll()
+}
+
+// ChatTitle implements admin.Backend; titles are fetched once and cached.
+func (b *Bot) ChatTitle(chatID int64) string {
+	return chatTitle(b.api, &b.chatTitles, chatID)
+}
+
+// Untrack implements admin.Backend.
+func (b *Bot) Untrack(key string) bool {
+	if b.ticketStore.Get(key) == nil {
+		return false
+	}
+	b.ticketStore.Delete(key)
+	b.log.Info("Ticket untracked", "key", key)
+	return true
+}
+
+// resyncRequest asks the poll loop to poll a ticket out of turn, so it is never polled concurrently.
+type resyncRequest struct {
+	key  string
+	done chan error
+}
+
+// Resync implements admin.Backend: runs one poll step for the ticket in the poll loop.
+func (b *Bot) Resync(ctx context.Context, key string) error {
+	req := resyncRequest{key: key, done: make(chan error, 1)}
+	select {
+	case b.resyncs <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bot) resyncTicket(ctx context.Context, key string) error {
+	ticket := b.ticketStore.Get(key)
+	if ticket == nil {
+		return errors.New("ticket is not tracked")
+	}
+	if b.pollTicket(ctx, ticket) == nil && b.ticketStore.Get(key) != nil {
+		return errors.New("failed to get issue status")
+	}
+	return nil
+}
+
+// ResendLastNotification implements admin.Backend.
+func (b *Bot) ResendLastNotification(key string) error {
+	b.notifications.mu.Lock()
+	sent, ok := b.notifications.byKey[key]
+	b.notifications.mu.Unlock()
+	if !ok {
+		return errors.New("no notification was sent since start")
+	}
+	_, err := sendMessage(b.api, sent.msg, sent.threadID)
+	return err
+}
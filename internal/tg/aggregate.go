@@ -2,55 +2,294 @@ package tg
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/store"
+	"telegram-bot-jira/internal/text"
+)
+
+const (
+	// aggregateProperty is the entity property of the aggregate issue holding the bot state.
+	aggregateProperty = "telegram-bot-state"
+	aggregateVersion  = 2
+	// aggregateMaxBytes is the size limit Jira puts on an entity property value.
+	aggregateMaxBytes = 32 * 1024
+	// viewTimeLayout is the time format of the description table; to the second, so that the table
+	// can stand in for the property without forwarding comments again.
+	viewTimeLayout = time.RFC3339
+	// legacyTimeLayout is the local time format of tables written by older versions.
+	legacyTimeLayout = "02.01.2006 15:04:05"
 )
 
-// updateAggregateToJira rebuilds the ADF table and updates the aggregate issue description.
+// aggregatePayload is the versioned machine-readable state stored in the aggregate issue.
+// The description table is only a rendered view of it.
+type aggregatePayload struct {
+	Version   int             `json:"version"`
+	Revision  int64           `json:"revision"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Tickets   []CreatedTicket `json:"tickets"`
+}
+
+// aggregateState is the last state read from or written to Jira: the base for merging concurrent edits.
+type aggregateState struct {
+	mu       sync.Mutex
+	revision int64
+	base     map[string]CreatedTicket
+	lastView string
+}
+
+func (a *aggregateState) reset(revision int64, tickets []CreatedTicket) {
+	a.revision = revision
+	a.base = make(map[string]CreatedTicket, len(tickets))
+	for _, ticket := range tickets {
+		a.base[ticket.Key] = ticket.UTC()
+	}
+}
+
+// updateAggregateToJira saves the ticket store into the aggregate issue property, merging changes
+// made concurrently by another writer, and refreshes the description table if it changed.
 func (b *Bot) updateAggregateToJira(ctx context.Context) {
 	if b.cfg.AggregateIssueKey == "" {
 		return
 	}
-	// Filter: include open and closed updated within a week
-	all := b.ticketStore.ListAll()
-
-	// Build table with Russian headers and proper UTF-8
-	header := []string{"Ключ", "Статус", "Название", "Чат ID", "Автор", "Последний коммент"}
-	rows := make([][]string, 0, len(all))
-	for _, ticket := range all {
-		// link := b.jira.BrowseURL(ticket.Key)
-		lastCommentAt := ""
-		if !ticket.LastCommentAt.IsZero() {
-			lastCommentAt = ticket.LastCommentAt.Format("02.01.2006 15:04:05")
-		}
-		rows = append(rows, []string{ticket.Key, ticket.Status, ticket.Name, int64ToString(ticket.ChatID), ticket.CreatorUsername, lastCommentAt})
+	b.aggregate.mu.Lock()
+	defer b.aggregate.mu.Unlock()
+
+	remote, err := b.readAggregatePayload(ctx)
+	if err != nil {
+		b.failAggregateSave(err)
+		return
+	}
+	tickets := b.ticketStore.ListAll()
+	revision := b.aggregate.revision + 1
+	if remote != nil && remote.Revision != b.aggregate.revision {
+		merged, upserts, deletes, conflicts := mergeTickets(b.aggregate.base, tickets, remote.Tickets)
+		b.ticketStore.Merge(upserts, deletes)
+		b.log.Warn("Aggregate issue changed concurrently, merged",
+			slog.Int64("known_revision", b.aggregate.revision), slog.Int64("remote_revision", remote.Revision),
+			slog.Int("from_remote", len(upserts)+len(deletes)), slog.Int("conflicts", conflicts))
+		tickets = merged
+		revision = max(revision, remote.Revision+1)
 	}
-	// Make column 4 (index) clickable link
-	doc := buildADFTable(header, rows, -1)
 
-	err := b.jira.UpdateIssueDescriptionADF(ctx, b.cfg.AggregateIssueKey, doc)
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].Key < tickets[j].Key })
+	for i := range tickets {
+		tickets[i] = tickets[i].UTC()
+	}
+	payload := aggregatePayload{Version: aggregateVersion, Revision: revision, UpdatedAt: time.Now().UTC(), Tickets: tickets}
+	payload, pruned, err := fitAggregatePayload(payload)
 	if err != nil {
+		// Retrying cannot help until tickets are removed; the next change of the store tries again.
+		b.health.storageResult(err)
 		b.log.Error("Error update jira context ticket", slog.String("key", b.cfg.AggregateIssueKey), slog.Any("err", err))
-	} else {
-		b.log.Info("Update jira context ticket", slog.String("key", b.cfg.AggregateIssueKey), slog.Int("size", len(all)))
+		return
+	}
+	if pruned > 0 {
+		b.log.Warn("Aggregate state is too large, closed tickets are not persisted",
+			slog.String("key", b.cfg.AggregateIssueKey), slog.Int("pruned", pruned))
+	}
+	tickets = payload.Tickets
+	if err := b.jira.SetIssueProperty(ctx, b.cfg.AggregateIssueKey, aggregateProperty, payload); err != nil {
+		b.failAggregateSave(err)
+		return
+	}
+	b.aggregate.reset(revision, tickets)
+	b.health.storageResult(nil)
+	b.log.Info("Update jira context ticket", slog.String("key", b.cfg.AggregateIssueKey), slog.Int("size", len(tickets)), slog.Int64("revision", revision))
+
+	b.updateAggregateView(ctx, tickets)
+}
+
+// fitAggregatePayload keeps the payload within aggregateMaxBytes by leaving out closed tickets,
+// those resolved first going first. Returns the number of tickets left out, or an error if open
+// tickets alone do not fit.
+func fitAggregatePayload(payload aggregatePayload) (aggregatePayload, int, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return payload, 0, err
+	}
+	size := len(data)
+	if size <= aggregateMaxBytes {
+		return payload, 0, nil
+	}
+	var closed []int
+	for i, ticket := range payload.Tickets {
+		if text.IsReadyStatus(ticket.Status) {
+			closed = append(closed, i)
+		}
+	}
+	sort.SliceStable(closed, func(i, j int) bool {
+		return payload.Tickets[closed[i]].ResolvedAt.Before(payload.Tickets[closed[j]].ResolvedAt)
+	})
+	drop := make(map[int]bool)
+	for _, i := range closed {
+		if size <= aggregateMaxBytes {
+			break
+		}
+		ticket, _ := json.Marshal(payload.Tickets[i])
+		size -= len(ticket) + 1 // with the separating comma
+		drop[i] = true
+	}
+	if size > aggregateMaxBytes {
+		return payload, 0, fmt.Errorf("aggregate state of %d open tickets is %d bytes, over the %d bytes Jira allows for an issue property; untrack tickets or use TICKET_STORAGE=properties",
+			len(payload.Tickets)-len(closed), size, aggregateMaxBytes)
+	}
+	kept := make([]CreatedTicket, 0, len(payload.Tickets)-len(drop))
+	for i, ticket := range payload.Tickets {
+		if !drop[i] {
+			kept = append(kept, ticket)
+		}
+	}
+	payload.Tickets = kept
+	return payload, len(drop), nil
+}
+
+func (b *Bot) failAggregateSave(err error) {
+	b.health.storageResult(err)
+	b.ticketStore.MarkDirty() // retry on the next poll cycle
+	b.log.Error("Error update jira context ticket", slog.String("key", b.cfg.AggregateIssueKey), slog.Any("err", err))
+}
+
+// updateAggregateView renders the human-readable table into the description, skipping unchanged views.
+func (b *Bot) updateAggregateView(ctx context.Context, tickets []CreatedTicket) {
+	lang := text.DefaultLang()
+	rows := make([][]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		rows = append(rows, []string{ticket.Key, ticket.Status, ticket.Name, int64ToString(ticket.ChatID), formatViewUser(ticket.Creator),
+			formatViewTime(ticket.LastCommentAt), formatViewTime(ticket.CreatedAt), formatViewTime(ticket.FirstResponseAt),
+			formatViewTime(ticket.ResolvedAt), strconv.Itoa(int(ticket.SLAFlags))})
+	}
+	doc := buildADFTable(text.TextAggregateHeading(lang), text.TextAggregateNote(lang, aggregateProperty), text.TextAggregateHeader(lang), rows, -1)
+	view, _ := json.Marshal(doc)
+	if string(view) == b.aggregate.lastView {
+		return
+	}
+	if err := b.jira.UpdateIssueDescriptionADF(ctx, b.cfg.AggregateIssueKey, doc); err != nil {
+		b.log.Warn("Error update jira context ticket view", slog.String("key", b.cfg.AggregateIssueKey), slog.Any("err", err))
+		return
 	}
+	b.aggregate.lastView = string(view)
 }
 
-// syncAggregateFromJira fetches aggregate issue description and populates ticket store.
+// readAggregatePayload returns the stored payload or nil if the issue has none yet.
+func (b *Bot) readAggregatePayload(ctx context.Context) (*aggregatePayload, error) {
+	raw, err := b.jira.GetIssueProperty(ctx, b.cfg.AggregateIssueKey, aggregateProperty)
+	if errors.Is(err, jira.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var payload aggregatePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("aggregate payload: %w", err)
+	}
+	if payload.Version > aggregateVersion {
+		return nil, fmt.Errorf("aggregate payload version %d is newer than supported %d", payload.Version, aggregateVersion)
+	}
+	if payload.Version < 2 {
+		var legacy struct {
+			Tickets []ticketV1 `json:"tickets"`
+		}
+		if err := json.Unmarshal(raw, &legacy); err != nil {
+			return nil, fmt.Errorf("aggregate payload: %w", err)
+		}
+		for i := range legacy.Tickets {
+			payload.Tickets[i] = legacy.Tickets[i].upgrade()
+		}
+	}
+	return &payload, nil
+}
+
+// syncAggregateFromJira loads the ticket store from the aggregate issue. Issues written by older
+// versions only have the description table; it is parsed once and saved in the new format.
 func (b *Bot) syncAggregateFromJira(ctx context.Context) error {
+	b.aggregate.mu.Lock()
+	payload, err := b.readAggregatePayload(ctx)
+	b.health.storageResult(err)
+	if err != nil {
+		b.aggregate.mu.Unlock()
+		return err
+	}
+	if payload != nil {
+		b.ticketStore.Init(payload.Tickets)
+		b.aggregate.reset(payload.Revision, payload.Tickets)
+		b.aggregate.mu.Unlock()
+		if payload.Version < aggregateVersion {
+			b.ticketStore.MarkDirty() // save in the current format
+		}
+		b.log.Info("Load jira context issue", slog.String("key", b.cfg.AggregateIssueKey), slog.Int("size", len(payload.Tickets)), slog.Int64("revision", payload.Revision))
+		return nil
+	}
+	b.aggregate.mu.Unlock()
+
 	doc, err := b.jira.GetIssueDescriptionADF(ctx, b.cfg.AggregateIssueKey)
+	b.health.storageResult(err)
 	if err != nil || doc == nil {
 		return err
 	}
 	tickets := parseAggregateADF(doc)
 	b.ticketStore.Init(tickets)
-	b.log.Info("Load jira context issue", slog.String("key", b.cfg.AggregateIssueKey), slog.Int("size", len(tickets)))
+	b.log.Info("Load jira context issue from legacy table", slog.String("key", b.cfg.AggregateIssueKey), slog.Int("size", len(tickets)))
+	b.ticketStore.MarkDirty()
 	return nil
 }
 
+// mergeTickets is a three-way merge of local and remote ticket lists against the last synced base.
+// A side that did not change a ticket takes the other side's version; when both changed it, local wins.
+// upserts and deletes are the remote changes to apply to the local store.
+func mergeTickets(base map[string]CreatedTicket, local, remote []CreatedTicket) (merged, upserts []CreatedTicket, deletes []string, conflicts int) {
+	localByKey := make(map[string]CreatedTicket, len(local))
+	remoteByKey := make(map[string]CreatedTicket, len(remote))
+	keys := make(map[string]bool)
+	for _, t := range local {
+		localByKey[t.Key] = t.UTC()
+		keys[t.Key] = true
+	}
+	for _, t := range remote {
+		remoteByKey[t.Key] = t.UTC()
+		keys[t.Key] = true
+	}
+	for key := range base {
+		keys[key] = true
+	}
+	changed := func(t CreatedTicket, ok bool, b CreatedTicket, inBase bool) bool {
+		return ok != inBase || ok && !t.Equal(b)
+	}
+	for key := range keys {
+		b, inBase := base[key]
+		l, inLocal := localByKey[key]
+		r, inRemote := remoteByKey[key]
+		localChanged := changed(l, inLocal, b, inBase)
+		remoteChanged := changed(r, inRemote, b, inBase)
+		switch {
+		case remoteChanged && !localChanged:
+			if inRemote {
+				merged = append(merged, r)
+				upserts = append(upserts, r)
+			} else if inLocal {
+				deletes = append(deletes, key)
+			}
+			continue
+		case remoteChanged && localChanged && (inLocal != inRemote || inLocal && !l.Equal(r)):
+			conflicts++
+		}
+		if inLocal {
+			merged = append(merged, l)
+		}
+	}
+	return merged, upserts, deletes, conflicts
+}
+
 func buildTableRow(cells []string, header bool) any {
 	cellType := "tableCell"
 	if header {
@@ -69,7 +308,7 @@ func buildTableRow(cells []string, header bool) any {
 
 func int64ToString(v int64) string { return fmt.Sprintf("%d", v) }
 
-// parseAggregateADF reads tickets from a simple ADF table as produced above.
+// parseAggregateADF reads tickets from the positional ADF table written by older versions.
 func parseAggregateADF(doc map[string]any) []CreatedTicket {
 	var out []CreatedTicket
 	if doc == nil {
@@ -114,14 +353,19 @@ func parseAggregateADF(doc map[string]any) []CreatedTicket {
 				return s
 			}
 			chatID := parseInt64(val(3))
-			lastCommentAt := parseLocalTime(val(5))
+			lastCommentAt := parseViewTime(val(5))
+			slaFlags, _ := strconv.Atoi(strings.TrimSpace(val(9)))
 			out = append(out, CreatedTicket{
 				Key:             val(0),
 				Status:          val(1),
 				Name:            val(2),
 				ChatID:          chatID,
-				CreatorUsername: val(4),
+				Creator:         parseViewUser(val(4)),
 				LastCommentAt:   lastCommentAt,
+				CreatedAt:       parseViewTime(val(6)),
+				FirstResponseAt: parseViewTime(val(7)),
+				ResolvedAt:      parseViewTime(val(8)),
+				SLAFlags:        uint8(slaFlags),
 			})
 		}
 		break
@@ -134,28 +378,72 @@ func parseInt64(s string) int64 {
 	return n
 }
 
-func parseLocalTime(s string) time.Time {
+func formatViewUser(u store.TelegramUser) string {
+	name := u.Name
+	if u.Username != "" {
+		name = "@" + u.Username
+	}
+	if u.ID != 0 {
+		name = strings.TrimSpace(name + " (" + int64ToString(u.ID) + ")")
+	}
+	return name
+}
+
+func formatViewTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(viewTimeLayout)
+}
+
+// parseViewUser reads a user written by formatViewUser, "@username (ID)" or "Name (ID)", or the bare
+// username of older versions.
+func parseViewUser(s string) store.TelegramUser {
+	s = strings.TrimSpace(s)
+	var u store.TelegramUser
+	if open := strings.LastIndex(s, " ("); open >= 0 && strings.HasSuffix(s, ")") {
+		if id, err := strconv.ParseInt(s[open+2:len(s)-1], 10, 64); err == nil {
+			u.ID = id
+			s = s[:open]
+		}
+	}
+	if name, ok := strings.CutPrefix(s, "@"); ok || u.ID == 0 {
+		u.Username = name
+	} else {
+		u.Name = s
+	}
+	return u
+}
+
+// parseViewTime reads a time written by formatViewTime or by older versions in local time.
+func parseViewTime(s string) time.Time {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return time.Time{}
 	}
-	if t, err := time.ParseInLocation("02.01.2006 15:04:05", s, time.Local); err == nil {
+	if t, err := time.Parse(viewTimeLayout, s); err == nil {
 		return t
 	}
-	t, _ := time.Parse("02.01.2006 15:04:05", s)
+	t, _ := time.ParseInLocation(legacyTimeLayout, s, time.Local)
 	return t
 }
 
 // buildADFTable builds ADF table; linkCol is index of column to render as clickable link (-1 if none).
-func buildADFTable(header []string, rows [][]string, linkCol int) map[string]any {
+func buildADFTable(heading, note string, header []string, rows [][]string, linkCol int) map[string]any {
 	doc := map[string]any{"type": "doc", "version": 1, "content": []any{}}
 	appendBlock := func(b any) { doc["content"] = append(doc["content"].([]any), b) }
 	// Heading
 	appendBlock(map[string]any{
 		"type":    "heading",
 		"attrs":   map[string]any{"level": 2},
-		"content": []any{map[string]any{"type": "text", "text": "Список тикетов бота"}},
+		"content": []any{map[string]any{"type": "text", "text": heading}},
 	})
+	if note != "" {
+		appendBlock(map[string]any{
+			"type":    "paragraph",
+			"content": []any{map[string]any{"type": "text", "text": note, "marks": []any{map[string]any{"type": "em"}}}},
+		})
+	}
 	// Table rows
 	tableRows := []any{buildTableRow(header, true)}
 	for _, r := range rows {
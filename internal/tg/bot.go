@@ -2,12 +2,17 @@
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"regexp"
 	"sync"
+	"time"
 
 	"telegram-bot-jira/internal/config"
 	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/metrics"
+	"telegram-bot-jira/internal/sla"
+	"telegram-bot-jira/internal/text"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -20,6 +25,21 @@ type Bot struct {
 	jira            *jira.Client
 	historyMessages *HistoryMessages
 	ticketStore     *TicketStore
+	sla             *sla.Policies
+	selfAccountID   string
+	health          healthState
+	notifications   notifications
+	cards           cards
+	identities      *identities
+	issueTemplates  map[int64]*issueTemplate
+	wizards         *wizards
+	searches        *searches
+	postedComments  *postedComments
+	resyncs         chan resyncRequest
+	watchersSynced  map[string]time.Time // issue "updated" at the last watcher sync, by key; poll loop only
+	aggregate       aggregateState
+	ticketIndex     ticketIndex
+	chatTitles      sync.Map // chat ID -> title, for the admin dashboard and private chats
 	cfg             config.Config
 }
 
@@ -32,6 +52,12 @@ func New(api *tgbotapi.BotAPI, log *slog.Logger, cfg config.Config, d *Dispatche
 		jira:            jiraClient,
 		historyMessages: NewHistoryMessages(cfg.HistoryMessagesLimit),
 		ticketStore:     NewTicketStore(),
+		wizards:         newWizards(),
+		searches:        newSearches(),
+		postedComments:  newPostedComments(),
+		resyncs:         make(chan resyncRequest),
+		watchersSynced:  make(map[string]time.Time),
+		health:          healthState{startedAt: time.Now()},
 		cfg:             cfg,
 	}
 }
@@ -40,10 +66,42 @@ func (b *Bot) Run(ctx context.Context) error {
 	if err := b.initCommands(); err != nil {
 		b.log.Warn("failed to initialize bot commands", "err", err)
 	}
+	if b.cfg.TicketStorage != storageProperties && b.cfg.TicketStorage != storageAggregate {
+		return fmt.Errorf("unknown TICKET_STORAGE %q", b.cfg.TicketStorage)
+	}
+	digests, err := newDigestPlans(b.cfg)
+	if err != nil {
+		return fmt.Errorf("digest schedule: %w", err)
+	}
+	if b.sla, err = sla.NewPolicies(b.cfg); err != nil {
+		return fmt.Errorf("sla: %w", err)
+	}
+	userMap, err := parseJiraUserMap(b.cfg.JiraUserMap)
+	if err != nil {
+		return fmt.Errorf("JIRA_USER_MAP: %w", err)
+	}
+	b.identities = newIdentities(userMap)
+	if b.issueTemplates, err = b.loadIssueTemplates(ctx); err != nil {
+		return fmt.Errorf("chat create settings: %w", err)
+	}
+	if err := checkWizardSteps(b.cfg); err != nil {
+		return fmt.Errorf("create wizard: %w", err)
+	}
+	if err := checkDuplicateCheck(b.cfg); err != nil {
+		return fmt.Errorf("duplicate check: %w", err)
+	}
+	if me, err := b.jira.Myself(ctx); err != nil {
+		b.log.Warn("failed to get jira account", "err", err)
+	} else {
+		b.selfAccountID = me.AccountID
+	}
 
 	b.updCfg.Timeout = 60
-	updatesChan := b.api.GetUpdatesChan(b.updCfg)
-	jobs := make(chan tgbotapi.Update, 1024)
+	updatesChan := make(chan incomingUpdate, 100)
+	go b.pollUpdates(ctx, updatesChan)
+	jobs := make(chan incomingUpdate, 1024)
+	metrics.NewGaugeFunc("jirabot_inbound_update_queue_depth", "Inbound Telegram updates waiting for a free worker.",
+		func() float64 { return float64(len(jobs)) })
 
 	var wg sync.WaitGroup
 	for i := 0; i < b.cfg.Workers; i++ {
@@ -53,40 +111,62 @@ func (b *Bot) Run(ctx context.Context) error {
 			for upd := range jobs {
 				ctx := &Ctx{
 					Std:             ctx,
-					Upd:             upd,
+					Upd:             upd.Update,
+					ThreadID:        upd.ThreadID,
+					Forum:           upd.Forum,
 					Log:             b.log.With("tg-worker", id),
 					Jira:            b.jira,
 					HistoryMessages: b.historyMessages,
 					TicketStore:     b.ticketStore,
+					SLA:             b.sla,
 					Params: CtxParams{
 						ReopenStatus:     b.cfg.JiraReopenStatus,
 						ProjectKey:       b.cfg.JiraProjectKey,
 						ProjectKeyRegexp: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(b.cfg.JiraProjectKey) + `-\d+\b`),
 						reactionEmoji:    b.cfg.TelegramReactionEmoji,
 						errorChatId:      int64(b.cfg.ErrorChatID),
+						chats:            b.cfg.Chats,
+						topicPerTicket:   b.cfg.TopicPerTicket,
+						identities:       b.identities,
+						linkIssueKey:     b.linkIssueKey(),
+						issueTemplates:   b.issueTemplates,
+						wizards:          b.wizards,
+						wizardSteps:      b.cfg.WizardSteps,
+						duplicateCheck:   b.cfg.DuplicateCheck,
+						searches:         b.searches,
+						postedComments:   b.postedComments,
 					},
 				}
 				ctx.Tg = &BotTgAction{
-					ctx:              ctx,
+					ctx:        ctx,
+					tgApi:      b.api,
+					chatTitles: &b.chatTitles,
+				}
+				if msg := upd.Message; msg != nil && msg.From != nil && !msg.From.IsBot {
+					b.ticketStore.UpdateUser(TelegramUserOf(msg.From))
 				}
 				err := b.dispatch.Dispatch(ctx)
 				if err != nil {
 					b.log.Error("failed to dispatch update", "err", err)
 				}
+				b.health.updateProcessed()
 			}
 		}(i)
 	}
 
-	// Initial sync from aggregate issue, if configured
-	if b.cfg.AggregateIssueKey != "" {
-		err := b.syncAggregateFromJira(ctx)
-		if err != nil {
-			b.log.Error("Error load jira context issue", "key", b.cfg.AggregateIssueKey)
-		}
+	// Initial load of tracked tickets
+	if err := b.loadTickets(ctx); err != nil {
+		b.log.Error("Error load tickets", "storage", b.cfg.TicketStorage, "err", err)
+	}
+	if err := b.loadIdentities(ctx); err != nil {
+		b.log.Error("Error load jira user links", "err", err)
+	} else {
+		b.migratePrivateChats()
 	}
 
 	// Background polling goroutine
 	go b.pollTickets(ctx)
+	go b.runDigests(ctx, digests)
 
 	for {
 		select {
@@ -100,12 +180,39 @@ func (b *Bot) Run(ctx context.Context) error {
 	}
 }
 
-func (b *Bot) initCommands() error {
-	commands := []tgbotapi.BotCommand{
-		{Command: "create_issue", Description: "Создать Jira задачу"},
-		{Command: "status_issue", Description: "Узнать статус Jira задачи"},
+// linkIssueKey is the issue whose notifications deliver /link_jira codes.
+func (b *Bot) linkIssueKey() string {
+	if b.cfg.JiraLinkIssueKey != "" {
+		return b.cfg.JiraLinkIssueKey
 	}
+	return b.cfg.AggregateIssueKey
+}
 
-	_, err := b.api.Request(tgbotapi.NewSetMyCommands(commands...))
-	return err
+// chatLang resolves the language for messages sent without a user context (polling, notifications).
+func (b *Bot) chatLang(chatID int64) text.Lang {
+	return text.ResolveLang(b.cfg.Chat(chatID).Language)
+}
+
+// initCommands registers the command menu for groups and private chats:
+// in the default language without language code and per catalog language with it.
+func (b *Bot) initCommands() error {
+	scopes := []struct {
+		scope   tgbotapi.BotCommandScope
+		private bool
+	}{
+		{tgbotapi.NewBotCommandScopeAllGroupChats(), false},
+		{tgbotapi.NewBotCommandScopeAllPrivateChats(), true},
+	}
+	for _, s := range scopes {
+		if _, err := b.api.Request(tgbotapi.NewSetMyCommandsWithScope(s.scope, BotCommands(text.DefaultLang(), s.private)...)); err != nil {
+			return err
+		}
+		for _, lang := range text.Langs {
+			cfg := tgbotapi.NewSetMyCommandsWithScopeAndLanguage(s.scope, string(lang), BotCommands(lang, s.private)...)
+			if _, err := b.api.Request(cfg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
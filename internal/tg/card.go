@@ -0,0 +1,158 @@
+package tg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Callback actions of ticket card buttons, "<action>|<key>".
+const (
+	CallbackReopen = "reopen"
+	CallbackStatus = "status"
+	CallbackWatch  = "watch"
+)
+
+// cardTimelineSize is the number of recent events shown on a live ticket card.
+const cardTimelineSize = 5
+
+// TicketKeyboard returns buttons of a ticket card: reopen and refresh for a ready ticket when reopening
+// is enabled, and follow.
+func TicketKeyboard(lang text.Lang, key, status, reopenStatus string) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if reopenStatus != "" && text.IsReadyStatus(status) {
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonReopen(lang), CallbackReopen+"|"+key)),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonRefreshStatus(lang), CallbackStatus+"|"+key)),
+		)
+	}
+	return append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text.ButtonFollow(lang), CallbackWatch+"|"+key)))
+}
+
+// cards holds what the live ticket cards currently show, by ticket key. It is kept in memory only:
+// timelines are not persisted with the tickets, where they would eat into the size limit of the
+// aggregate property, so after a restart a card keeps its last rendered timeline until the next
+// change and then shows only the events seen since the restart.
+type cards struct {
+	mu    sync.Mutex
+	byKey map[string]*cardState
+}
+
+type cardState struct {
+	summary                    string
+	status, assignee, priority string
+	comments                   int // -1 until known
+	events                     []text.TimelineEvent
+	lastEdit                   time.Time
+	dirty                      bool // changed since the last edit
+}
+
+// observe records changes between the card and the fetched issue as timeline events.
+func (s *cardState) observe(issue *jira.IssueStatus, comments int, now time.Time) {
+	assignee := strings.TrimSpace(issue.Assignee)
+	changes := []struct{ kind, from, to string }{
+		{"status", s.status, issue.Status},
+		{"assignee", s.assignee, assignee},
+		{"priority", s.priority, issue.Priority},
+	}
+	if comments >= 0 && s.comments >= 0 {
+		changes = append(changes, struct{ kind, from, to string }{"comments", strconv.Itoa(s.comments), strconv.Itoa(comments)})
+	}
+	for _, c := range changes {
+		if c.from != c.to {
+			s.events = append(s.events, text.TimelineEvent{At: now, Kind: c.kind, From: c.from, To: c.to})
+			s.dirty = true
+		}
+	}
+	// A renamed ticket is redrawn without a timeline event.
+	if s.summary != issue.Summary {
+		s.summary = issue.Summary
+		s.dirty = true
+	}
+	if len(s.events) > cardTimelineSize {
+		s.events = s.events[len(s.events)-cardTimelineSize:]
+	}
+	s.status, s.assignee, s.priority = issue.Status, assignee, issue.Priority
+	if comments >= 0 {
+		s.comments = comments
+	}
+}
+
+// refreshTicketCard edits the ticket message in place when status, assignee, priority or
+// comment count changed. Edits are throttled per ticket by CARD_EDIT_INTERVAL; pending
+// changes are applied on a later poll. comments is -1 if the comments could not be fetched.
+func (b *Bot) refreshTicketCard(ticket *CreatedTicket, issue *jira.IssueStatus, comments int) {
+	if ticket.MessageID == 0 || issue == nil {
+		return
+	}
+	now := time.Now()
+	b.cards.mu.Lock()
+	if b.cards.byKey == nil {
+		b.cards.byKey = make(map[string]*cardState)
+	}
+	state, ok := b.cards.byKey[ticket.Key]
+	if !ok {
+		// The card was rendered from the same data when it was sent; only later changes are events.
+		state = &cardState{comments: -1}
+		state.observe(issue, comments, now)
+		state.events, state.dirty = nil, false
+		b.cards.byKey[ticket.Key] = state
+		b.cards.mu.Unlock()
+		return
+	}
+	state.observe(issue, comments, now)
+	interval := time.Duration(b.cfg.CardEditInterval) * time.Second
+	if !state.dirty || now.Sub(state.lastEdit) < interval {
+		b.cards.mu.Unlock()
+		return
+	}
+	data := text.NewTicketStatusData(b.chatLang(ticket.ChatID), issue, ticket.Name, ticket.Creator, b.ticketSLA(ticket, issue.Priority))
+	data.Comments = state.comments
+	data.Timeline = append([]text.TimelineEvent(nil), state.events...)
+	state.dirty = false
+	state.lastEdit = now
+	b.cards.mu.Unlock()
+
+	if err := b.editTicketCard(ticket, issue, data); err != nil {
+		b.log.Warn("Failed to update ticket card", "key", ticket.Key, "message_id", ticket.MessageID, "error", err)
+		b.cards.mu.Lock()
+		state.dirty = true
+		b.cards.mu.Unlock()
+	}
+}
+
+func (b *Bot) editTicketCard(ticket *CreatedTicket, issue *jira.IssueStatus, data text.TicketStatusData) error {
+	lang := b.chatLang(ticket.ChatID)
+	edit := tgbotapi.NewEditMessageText(ticket.ChatID, ticket.MessageID, text.TextTicketCardHTML(lang, data))
+	edit.ParseMode = tgbotapi.ModeHTML
+	if rows := TicketKeyboard(lang, ticket.Key, issue.Status, b.cfg.JiraReopenStatus); len(rows) > 0 {
+		markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+		edit.ReplyMarkup = &markup
+	}
+	_, err := b.api.Send(edit)
+	if err != nil && strings.Contains(err.Error(), "message is not modified") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+	return nil
+}
+
+// pruneCards forgets cards of tickets that are no longer tracked.
+func (b *Bot) pruneCards() {
+	b.cards.mu.Lock()
+	defer b.cards.mu.Unlock()
+	for key := range b.cards.byKey {
+		if b.ticketStore.Get(key) == nil {
+			delete(b.cards.byKey, key)
+		}
+	}
+}
@@ -1,6 +1,62 @@
 package tg
 
-import "strings"
+import (
+	"strings"
+
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Command describes a bot command: it is registered in the Telegram menu
+// and listed in the /help guide. Descriptions live in the text catalog
+// under "command.<name>".
+type Command struct {
+	Name    string
+	Group   bool // shown in group chats
+	Private bool // shown in private chats
+}
+
+// Commands is the registry of all commands supported by the bot.
+var Commands = []Command{
+	{Name: "create_issue", Group: true},
+	{Name: "status_issue", Group: true},
+	{Name: "cancel", Group: true},
+	{Name: "watch", Group: true, Private: true},
+	{Name: "unwatch", Group: true, Private: true},
+	{Name: "assign", Group: true, Private: true},
+	{Name: "track", Group: true},
+	{Name: "untrack", Group: true},
+	{Name: "search", Group: true, Private: true},
+	{Name: "my_tickets", Private: true},
+	{Name: "notify_private", Private: true},
+	{Name: "link_jira", Private: true},
+	{Name: "unlink_jira", Private: true},
+	{Name: "help", Group: true, Private: true},
+	{Name: "start", Private: true},
+}
+
+// BotCommands returns localized commands available in a private or group chat.
+func BotCommands(lang text.Lang, private bool) []tgbotapi.BotCommand {
+	var out []tgbotapi.BotCommand
+	for _, c := range Commands {
+		if private && c.Private || !private && c.Group {
+			out = append(out, tgbotapi.BotCommand{Command: c.Name, Description: text.CommandDescription(lang, c.Name)})
+		}
+	}
+	return out
+}
+
+// IsCommand reports whether text starts with the "/name" or "/name@botname" command token.
+func IsCommand(text, name string) bool {
+	s := strings.TrimSpace(text)
+	token := s
+	if i := strings.IndexAny(s, " \t\n\r"); i >= 0 {
+		token = s[:i]
+	}
+	token, _, _ = strings.Cut(token, "@")
+	return token == "/"+name
+}
 
 // StripCommandText removes any leading telegram command token
 // like "/command" or "/command@botname" from the start of text
@@ -23,4 +79,4 @@ func StripCommandText(text string) string {
         s = ""
     }
     return strings.TrimSpace(s)
-}
\ No newline at end of file
+}
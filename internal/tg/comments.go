@@ -0,0 +1,58 @@
+package tg
+
+import (
+	"sync"
+	"time"
+
+	"telegram-bot-jira/internal/common"
+)
+
+// postedCommentTTL is how long IDs of comments posted by the bot are kept; the poll loop reads new
+// comments long before.
+const postedCommentTTL = 24 * time.Hour
+
+// postedComments holds IDs of comments the bot posted to Jira from Telegram, so that the poll loop does
+// not send them back to the chat. Comments the bot's Jira account writes in Jira itself are forwarded.
+type postedComments struct {
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+func newPostedComments() *postedComments {
+	return &postedComments{ids: make(map[string]time.Time)}
+}
+
+func (p *postedComments) add(id string) {
+	if id == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for old, at := range p.ids {
+		if time.Since(at) > postedCommentTTL {
+			delete(p.ids, old)
+		}
+	}
+	p.ids[id] = time.Now()
+}
+
+func (p *postedComments) has(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.ids[id]
+	return ok
+}
+
+// AddComment posts a comment to the Jira issue on behalf of the bot.
+func (c *Ctx) AddComment(key, body string) error {
+	id, err := c.Jira.AddComment(c.Std, key, body)
+	c.Params.postedComments.add(id)
+	return err
+}
+
+// AddCommentWithFiles posts a comment with files to the Jira issue on behalf of the bot.
+func (c *Ctx) AddCommentWithFiles(key, body, fileLabel string, files []common.FileInfo) error {
+	id, err := c.Jira.AddCommentWithEmbeddedFiles(c.Std, key, body, fileLabel, files)
+	c.Params.postedComments.add(id)
+	return err
+}
@@ -8,10 +8,15 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"telegram-bot-jira/internal/common"
+	"telegram-bot-jira/internal/config"
 	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/sla"
 	"telegram-bot-jira/internal/store"
+	"telegram-bot-jira/internal/text"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -20,10 +25,13 @@ type Ctx struct {
 	Std             context.Context
 	Tg              *BotTgAction
 	Upd             tgbotapi.Update
+	ThreadID        int  // forum topic of the update, 0 for General or regular chats
+	Forum           bool // the current chat is a forum
 	Log             *slog.Logger
 	Jira            *jira.Client
 	HistoryMessages *HistoryMessages
 	TicketStore     *store.TicketStore
+	SLA             *sla.Policies
 	Params          CtxParams
 }
 
@@ -33,11 +41,22 @@ type CtxParams struct {
 	ProjectKeyRegexp *regexp.Regexp
 	reactionEmoji    string
 	errorChatId      int64
+	chats            map[int64]config.ChatSettings
+	topicPerTicket   func(chatID int64) bool
+	identities       *identities
+	linkIssueKey     string
+	issueTemplates   map[int64]*issueTemplate
+	wizards          *wizards
+	wizardSteps      []string
+	duplicateCheck   string
+	searches         *searches
+	postedComments   *postedComments
 }
 
 type BotTgAction struct {
-	ctx   *Ctx
-	tgApi *tgbotapi.BotAPI
+	ctx        *Ctx
+	tgApi      *tgbotapi.BotAPI
+	chatTitles *sync.Map
 }
 
 type reactionType struct {
@@ -45,15 +64,79 @@ type reactionType struct {
 	Emoji string `json:"emoji,omitempty"`
 }
 
+// Chat returns per-chat settings overrides.
+func (p CtxParams) Chat(chatID int64) config.ChatSettings {
+	return p.chats[chatID]
+}
+
+// Lang resolves the language for the current update: chat setting, then sender's Telegram language, then default.
+func (c *Ctx) Lang() text.Lang {
+	chatLang := ""
+	if chat := c.Tg.CurrentChat(); chat != nil {
+		chatLang = c.Params.Chat(chat.ID).Language
+	}
+	userLang := ""
+	if user := c.Tg.CurrentUser(); user != nil {
+		userLang = user.LanguageCode
+	}
+	return text.ResolveLang(chatLang, userLang)
+}
+
+// TicketSLA evaluates the SLA of a stored ticket with the given Jira priority; nil if no SLA applies.
+func (c *Ctx) TicketSLA(ticket *CreatedTicket, priority string) *sla.Status {
+	return evaluateSLA(c.SLA, ticket, priority)
+}
+
+func evaluateSLA(policies *sla.Policies, ticket *CreatedTicket, priority string) *sla.Status {
+	policy := policies.For(ticket.ChatID, priority)
+	if policy == nil || ticket.Tracked {
+		return nil
+	}
+	status := policy.Evaluate(ticketSLATimes(ticket), time.Now())
+	return &status
+}
+
+// TopicPerTicket reports whether a forum chat gets a dedicated topic for each new ticket.
+func (p CtxParams) TopicPerTicket(chatID int64) bool {
+	return p.topicPerTicket(chatID)
+}
+
+// HasErrorChat reports whether a service chat for error reports is configured.
+func (p CtxParams) HasErrorChat() bool {
+	return p.errorChatId != 0
+}
+
 func (bot *BotTgAction) SelfUserName() string {
 	return bot.tgApi.Self.UserName
 }
 
-func (bot *BotTgAction) CurrentChatId() int64 {
+func (bot *BotTgAction) CurrentChat() *tgbotapi.Chat {
 	if bot.ctx.Upd.Message != nil && bot.ctx.Upd.Message.Chat != nil {
-		return bot.ctx.Upd.Message.Chat.ID
+		return bot.ctx.Upd.Message.Chat
 	} else if bot.ctx.Upd.CallbackQuery != nil && bot.ctx.Upd.CallbackQuery.Message != nil && bot.ctx.Upd.CallbackQuery.Message.Chat != nil {
-		return bot.ctx.Upd.CallbackQuery.Message.Chat.ID
+		return bot.ctx.Upd.CallbackQuery.Message.Chat
+	} else if bot.ctx.Upd.MyChatMember != nil {
+		return &bot.ctx.Upd.MyChatMember.Chat
+	}
+	return nil
+}
+
+func (bot *BotTgAction) CurrentUser() *tgbotapi.User {
+	if bot.ctx.Upd.Message != nil {
+		return bot.ctx.Upd.Message.From
+	} else if bot.ctx.Upd.CallbackQuery != nil {
+		return bot.ctx.Upd.CallbackQuery.From
+	} else if bot.ctx.Upd.InlineQuery != nil {
+		return bot.ctx.Upd.InlineQuery.From
+	} else if bot.ctx.Upd.MyChatMember != nil {
+		return &bot.ctx.Upd.MyChatMember.From
+	}
+	return nil
+}
+
+func (bot *BotTgAction) CurrentChatId() int64 {
+	if chat := bot.CurrentChat(); chat != nil {
+		return chat.ID
 	}
 	return 0
 }
@@ -69,10 +152,10 @@ func (bot *BotTgAction) TryGetTicketKeyInMessage() string {
 
 func (bot *BotTgAction) SendMessageErrorChat(text string) error {
 	chatId := bot.ctx.Params.errorChatId
-	if chatId != 0 {
+	if chatId == 0 {
 		return nil
 	}
-	if bot.ctx.Upd.Message == nil || bot.ctx.Upd.Message.Chat == nil {
+	if bot.CurrentChat() == nil {
 		bot.ctx.Log.Error("cannot send message: chat not found")
 		return nil
 	}
@@ -81,7 +164,7 @@ func (bot *BotTgAction) SendMessageErrorChat(text string) error {
 }
 
 func (bot *BotTgAction) SendMessage(text string) error {
-	_, err := bot.tgApi.Send(tgbotapi.NewMessage(bot.CurrentChatId(), text))
+	_, err := sendMessage(bot.tgApi, tgbotapi.NewMessage(bot.CurrentChatId(), text), bot.ctx.ThreadID)
 	return err
 }
 
@@ -91,14 +174,30 @@ func (bot *BotTgAction) EmptyCallback() error {
 	return err
 }
 
+// AnswerCallback answers the current callback query with a short notification.
+func (bot *BotTgAction) AnswerCallback(text string) error {
+	_, err := bot.tgApi.Request(tgbotapi.NewCallback(bot.ctx.Upd.CallbackQuery.ID, text))
+	return err
+}
+
 func (bot *BotTgAction) SendMessageHTML(text string, buttons ...[]tgbotapi.InlineKeyboardButton) error {
+	_, err := bot.SendHTML(text, buttons...)
+	return err
+}
+
+// SendHTML sends an HTML message into the current chat topic and returns the sent message.
+func (bot *BotTgAction) SendHTML(text string, buttons ...[]tgbotapi.InlineKeyboardButton) (tgbotapi.Message, error) {
 	msg := tgbotapi.NewMessage(bot.CurrentChatId(), text)
 	msg.ParseMode = tgbotapi.ModeHTML
 	if len(buttons) > 0 {
 		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons...)
 	}
-	_, err := bot.tgApi.Send(msg)
-	return err
+	return sendMessage(bot.tgApi, msg, bot.ctx.ThreadID)
+}
+
+// CreateForumTopic creates a topic in the current forum chat and returns its thread ID.
+func (bot *BotTgAction) CreateForumTopic(name string) (int, error) {
+	return createForumTopic(bot.tgApi, bot.CurrentChatId(), name)
 }
 
 func (bot *BotTgAction) ReactCurrentMessageIsRead() {
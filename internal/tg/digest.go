@@ -0,0 +1,220 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"telegram-bot-jira/internal/config"
+	"telegram-bot-jira/internal/schedule"
+	"telegram-bot-jira/internal/store"
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// digestPlan is a resolved digest schedule of one chat.
+type digestPlan struct {
+	cron      *schedule.Cron
+	loc       *time.Location
+	pin       bool
+	staleDays int
+}
+
+// digestPlans holds the default plan and per-chat overrides; nil plan means no digest.
+type digestPlans struct {
+	def    *digestPlan
+	byChat map[int64]*digestPlan
+}
+
+// newDigestPlans parses DIGEST_* settings and chat overrides so that mistakes fail at startup.
+func newDigestPlans(cfg config.Config) (*digestPlans, error) {
+	def, err := buildDigestPlan(config.DigestSettings{}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	plans := &digestPlans{def: def, byChat: make(map[int64]*digestPlan)}
+	for chatID, chat := range cfg.Chats {
+		plan, err := buildDigestPlan(chat.Digest, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("chat %d: %w", chatID, err)
+		}
+		plans.byChat[chatID] = plan
+	}
+	return plans, nil
+}
+
+func buildDigestPlan(s config.DigestSettings, cfg config.Config) (*digestPlan, error) {
+	spec := s.Schedule
+	if spec == "" {
+		spec = cfg.DigestSchedule
+	}
+	if s.Disabled || spec == "" {
+		return nil, nil
+	}
+	cron, err := schedule.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	tz := s.Timezone
+	if tz == "" {
+		tz = cfg.DigestTimezone
+	}
+	loc := time.Local
+	if tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, err
+		}
+	}
+	plan := &digestPlan{cron: cron, loc: loc, pin: cfg.DigestPin, staleDays: cfg.DigestStaleDays}
+	if s.Pin != nil {
+		plan.pin = *s.Pin
+	}
+	if s.StaleDays > 0 {
+		plan.staleDays = s.StaleDays
+	}
+	return plan, nil
+}
+
+func (p *digestPlans) forChat(chatID int64) *digestPlan {
+	if plan, ok := p.byChat[chatID]; ok {
+		return plan
+	}
+	return p.def
+}
+
+// runDigests checks every minute which chats have a digest scheduled and posts it.
+func (b *Bot) runDigests(ctx context.Context, plans *digestPlans) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	last := time.Now().Truncate(time.Minute)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			now = now.Truncate(time.Minute)
+			// Catch up minutes skipped by a delayed tick.
+			for at := last.Add(time.Minute); !at.After(now); at = at.Add(time.Minute) {
+				b.sendScheduledDigests(ctx, plans, at)
+			}
+			last = now
+		}
+	}
+}
+
+func (b *Bot) sendScheduledDigests(ctx context.Context, plans *digestPlans, at time.Time) {
+	for _, thread := range b.digestThreads() {
+		plan := plans.forChat(thread.ChatID)
+		if plan == nil || !plan.cron.Matches(at.In(plan.loc)) {
+			continue
+		}
+		if err := b.sendDigest(ctx, thread, plan, at.In(plan.loc)); err != nil {
+			b.log.Error("Failed to send digest", "chat", thread.ChatID, "thread", thread.ThreadID, "err", err)
+		}
+	}
+}
+
+// digestThreads returns where digests go: each forum topic with tickets gets its own digest,
+// except chats with a topic per ticket, which get one digest for the whole chat in General.
+func (b *Bot) digestThreads() []store.ChatThread {
+	seen := make(map[store.ChatThread]bool)
+	var out []store.ChatThread
+	for _, thread := range b.ticketStore.Threads() {
+		if b.cfg.TopicPerTicket(thread.ChatID) {
+			thread.ThreadID = 0
+		}
+		if !seen[thread] {
+			seen[thread] = true
+			out = append(out, thread)
+		}
+	}
+	return out
+}
+
+// sendDigest posts open tickets of the chat topic grouped by status, with fresh data fetched from Jira in one batch.
+func (b *Bot) sendDigest(ctx context.Context, thread store.ChatThread, plan *digestPlan, now time.Time) error {
+	chatID := thread.ChatID
+	tickets := b.ticketStore.ListByThread(chatID, thread.ThreadID)
+	if b.cfg.TopicPerTicket(chatID) {
+		tickets = b.ticketStore.ListByChatID(chatID)
+	}
+	var keys []string
+	for _, ticket := range tickets {
+		if !text.IsReadyStatus(ticket.Status) {
+			keys = append(keys, ticket.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	issues, err := b.jira.GetIssuesStatus(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	data := text.DailyDigestData{Date: now, StaleDays: plan.staleDays}
+	groups := make(map[string]*text.DigestGroup)
+	var order []string
+	for _, key := range keys {
+		issue := issues[key]
+		if issue == nil || text.IsReadyStatus(issue.Status) {
+			continue
+		}
+		ticket := b.ticketStore.Get(key)
+		name := issue.Summary
+		if ticket != nil && ticket.Name != "" {
+			name = ticket.Name
+		}
+		stale := plan.staleDays > 0 && now.Sub(issue.Updated) > time.Duration(plan.staleDays)*24*time.Hour
+		if stale {
+			data.StaleCount++
+		}
+		group, ok := groups[issue.Status]
+		if !ok {
+			group = &text.DigestGroup{Status: issue.Status}
+			groups[issue.Status] = group
+			order = append(order, issue.Status)
+		}
+		group.Tickets = append(group.Tickets, text.DigestTicket{
+			Key:      issue.Key,
+			Name:     name,
+			Assignee: issue.Assignee,
+			URL:      b.jira.BrowseURL(issue.Key),
+			AgeDays:  int(now.Sub(issue.Created).Hours() / 24),
+			Stale:    stale,
+		})
+		data.Total++
+	}
+	if data.Total == 0 {
+		return nil
+	}
+	sort.Strings(order)
+	for _, status := range order {
+		group := groups[status]
+		sortDigestTickets(group.Tickets)
+		data.Groups = append(data.Groups, *group)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text.TextDailyDigestHTML(b.chatLang(chatID), data))
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	sent, err := sendMessage(b.api, msg, thread.ThreadID)
+	if err != nil {
+		return err
+	}
+	b.log.Info("Digest sent", "chat", chatID, "thread", thread.ThreadID, "tickets", data.Total)
+	if plan.pin {
+		pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+		if _, err := b.api.Request(pin); err != nil {
+			b.log.Warn("Failed to pin digest", "chat", chatID, "err", err)
+		}
+	}
+	return nil
+}
+
+// sortDigestTickets puts the oldest tickets first.
+func sortDigestTickets(tickets []text.DigestTicket) {
+	sort.SliceStable(tickets, func(i, j int) bool { return tickets[i].AgeDays > tickets[j].AgeDays })
+}
@@ -3,6 +3,7 @@ package tg
 import (
 	"strings"
 
+	"telegram-bot-jira/internal/metrics"
 	"telegram-bot-jira/internal/text"
 )
 
@@ -12,6 +13,20 @@ type Dispatcher struct {
 	OnGetIssue           HandlerFunc
 	OnReplyBotForComment HandlerFunc
 	OnMediaGroup         HandlerFunc
+	OnHelp               HandlerFunc
+	OnMyTickets          HandlerFunc
+	OnNotifyPrivate      HandlerFunc
+	OnWatch              HandlerFunc
+	OnUnwatch            HandlerFunc
+	OnAssign             HandlerFunc
+	OnTrack              HandlerFunc
+	OnUntrack            HandlerFunc
+	OnSearch             HandlerFunc
+	OnInlineQuery        HandlerFunc
+	OnWizard             HandlerFunc
+	OnLinkJira           HandlerFunc
+	OnUnlinkJira         HandlerFunc
+	OnBotAdded           HandlerFunc
 }
 
 func NewDispatcher() *Dispatcher {
@@ -19,38 +34,131 @@ func NewDispatcher() *Dispatcher {
 }
 
 func (d *Dispatcher) Dispatch(ctx *Ctx) error {
+	name, handler := d.route(ctx)
+	metrics.TelegramUpdates.Inc(updateType(ctx), name)
+	if handler == nil {
+		return nil
+	}
+	err := handler(ctx)
+	if err != nil {
+		metrics.HandlerErrors.Inc(name)
+	}
+	return err
+}
+
+// route выбирает обработчик апдейта; имя обработчика используется в метриках.
+func (d *Dispatcher) route(ctx *Ctx) (string, HandlerFunc) {
 	update := ctx.Upd
+	if update.MyChatMember != nil && d.OnBotAdded != nil {
+		return "bot_added", d.OnBotAdded
+	}
 	if update.Message != nil {
 		message := update.Message
+		// Справка: /help в любом чате, /start в личке
+		if IsCommand(message.Text, "help") || IsCommand(message.Text, "start") && message.Chat.IsPrivate() {
+			return "help", d.OnHelp
+		}
+
+		// Тикеты автора в личке с ботом
+		if message.Chat.IsPrivate() {
+			if IsCommand(message.Text, "my_tickets") {
+				return "my_tickets", d.OnMyTickets
+			}
+			if IsCommand(message.Text, "notify_private") {
+				return "notify_private", d.OnNotifyPrivate
+			}
+			if IsCommand(message.Text, "link_jira") {
+				return "link_jira", d.OnLinkJira
+			}
+			if IsCommand(message.Text, "unlink_jira") {
+				return "unlink_jira", d.OnUnlinkJira
+			}
+		}
+
+		// Подписка на тикет
+		if IsCommand(message.Text, "watch") {
+			return "watch", d.OnWatch
+		}
+		if IsCommand(message.Text, "unwatch") {
+			return "unwatch", d.OnUnwatch
+		}
+
+		// Назначение тикета
+		if IsCommand(message.Text, "assign") {
+			return "assign", d.OnAssign
+		}
+
+		// Поиск задач Jira
+		if IsCommand(message.Text, "search") {
+			return "search", d.OnSearch
+		}
+
+		// Подключение существующих задач Jira к чату
+		if IsCommand(message.Text, "track") {
+			return "track", d.OnTrack
+		}
+		if IsCommand(message.Text, "untrack") {
+			return "untrack", d.OnUntrack
+		}
+
+		// Ответ на шаг мастера создания тикета и его отмена
+		if IsCommand(message.Text, "cancel") || ctx.IsWizardReply(message) {
+			return "wizard", d.OnWizard
+		}
+
 		// Проверяем создание задачи
 		if strings.HasPrefix(message.Text, "/create_issue") || strings.HasPrefix(message.Text, "@"+ctx.Tg.SelfUserName()) {
-			return d.OnCreateIssue(ctx)
+			return "create_issue", d.OnCreateIssue
 		}
 
 		// Проверяем статус задачи
 		if strings.HasPrefix(message.Text, "/status_issue") {
 			// || message.ReplyToMessage != nil && message.ReplyToMessage.From.UserName == ctx.Bot.Self.UserName
-			return d.OnGetIssue(ctx)
+			return "status_issue", d.OnGetIssue
 		}
 
 		if message.ReplyToMessage != nil {
 			// Check if this message is part of a media group and needs batching
 			if message.Photo != nil || message.Document != nil || message.Video != nil || message.Audio != nil || message.Voice != nil {
-				return d.OnMediaGroup(ctx)
+				return "media_comment", d.OnMediaGroup
 			}
 			// Проверяем ответ на задачу - есть реплай, автор релпая бот, в сообщении есть ключ задачи и якорь для ответа
 			if message.ReplyToMessage != nil && message.ReplyToMessage.From.UserName == ctx.Tg.SelfUserName() {
-				if strings.Contains(message.ReplyToMessage.Text, text.TextAnchorReplyJiraToTelegram()) ||
-					strings.Contains(message.ReplyToMessage.Text, text.TextAnchorReplyStatusToJira()) {
-					return d.OnReplyBotForComment(ctx)
+				if text.IsReplyAnchor(message.ReplyToMessage.Text) {
+					return "comment", d.OnReplyBotForComment
 				}
 			}
 		}
-		ctx.HistoryMessages.AddMessage(update.Message)
-		return nil
+		return "history", func(ctx *Ctx) error {
+			ctx.HistoryMessages.AddMessage(ctx.Upd.Message, ctx.ThreadID)
+			return nil
+		}
 	}
 	if update.CallbackQuery != nil && d.OnCallback != nil {
-		return d.OnCallback(ctx)
+		return "callback", d.OnCallback
+	}
+	// Инлайн-режим: @bot KEY-123 или текст в любом чате
+	if update.InlineQuery != nil && d.OnInlineQuery != nil {
+		return "inline_query", d.OnInlineQuery
+	}
+	return "none", nil
+}
+
+func updateType(ctx *Ctx) string {
+	switch upd := ctx.Upd; {
+	case upd.Message != nil:
+		return "message"
+	case upd.EditedMessage != nil:
+		return "edited_message"
+	case upd.CallbackQuery != nil:
+		return "callback_query"
+	case upd.InlineQuery != nil:
+		return "inline_query"
+	case upd.MyChatMember != nil:
+		return "my_chat_member"
+	case upd.ChannelPost != nil:
+		return "channel_post"
+	default:
+		return "other"
 	}
-	return nil
 }
@@ -0,0 +1,142 @@
+package tg
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"telegram-bot-jira/internal/config"
+	"telegram-bot-jira/internal/text"
+)
+
+// Scopes of the duplicate check before a ticket is created.
+const (
+	duplicateCheckOff     = "off"
+	duplicateCheckChat    = "chat"    // open tickets of the chat
+	duplicateCheckProject = "project" // and tickets of the chat found by a Jira text search
+)
+
+const (
+	// duplicateMax is the number of candidates offered.
+	duplicateMax = 3
+	// duplicateOverlap is the share of the shorter text's words that a similar summary has.
+	duplicateOverlap = 0.6
+	// duplicateSearchMax is the number of issues fetched by the Jira text search; only tickets of the
+	// chat are kept, so it is larger than duplicateMax.
+	duplicateSearchMax = 20
+	// duplicateSearchTerms limits words of the Jira text search, which matches issues having all of them.
+	duplicateSearchTerms = 5
+	// duplicateStemLen cuts words to a crude stem, so word forms match ("принтер", "принтера").
+	duplicateStemLen = 5
+)
+
+// Duplicate is an open ticket similar to the one being created.
+type Duplicate struct {
+	Key     string
+	Summary string
+	Status  string
+}
+
+var (
+	duplicateWordRe    = regexp.MustCompile(`[\p{L}\p{N}]{3,}`)
+	duplicateStopWords = []string{"the", "and", "for", "not", "with", "что", "как", "для", "это", "при", "или", "нет", "уже", "все", "так"}
+)
+
+// checkDuplicateCheck validates DUPLICATE_CHECK and the chat settings.
+func checkDuplicateCheck(cfg config.Config) error {
+	scopes := []string{duplicateCheckOff, duplicateCheckChat, duplicateCheckProject}
+	if !slices.Contains(scopes, cfg.DuplicateCheck) {
+		return fmt.Errorf("unknown scope %q, want one of %v", cfg.DuplicateCheck, scopes)
+	}
+	for chatID, chat := range cfg.Chats {
+		if chat.DuplicateCheck != "" && !slices.Contains(scopes, chat.DuplicateCheck) {
+			return fmt.Errorf("chat %d: unknown scope %q, want one of %v", chatID, chat.DuplicateCheck, scopes)
+		}
+	}
+	return nil
+}
+
+// duplicateWords returns the stems of significant words of s.
+func duplicateWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range duplicateWordRe.FindAllString(strings.ToLower(s), -1) {
+		if slices.Contains(duplicateStopWords, w) {
+			continue
+		}
+		if runes := []rune(w); len(runes) > duplicateStemLen {
+			w = string(runes[:duplicateStemLen])
+		}
+		words[w] = true
+	}
+	return words
+}
+
+// similarWords reports whether most words of the shorter text are in the other one.
+func similarWords(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	common := 0
+	for w := range a {
+		if b[w] {
+			common++
+		}
+	}
+	shorter := min(len(a), len(b))
+	return common >= min(2, shorter) && float64(common) >= duplicateOverlap*float64(shorter)
+}
+
+// FindDuplicates returns open tickets similar to the text: tickets of the chat with a similar name
+// and, with the "project" scope, tickets of the chat found by a Jira text search, e.g. by description
+// or comments. Issues of other chats are never offered: their summaries are not for this chat.
+func (c *Ctx) FindDuplicates(chatID int64, s string) []Duplicate {
+	scope := c.Params.Chat(chatID).DuplicateCheck
+	if scope == "" {
+		scope = c.Params.duplicateCheck
+	}
+	words := duplicateWords(s)
+	if scope == duplicateCheckOff || len(words) == 0 {
+		return nil
+	}
+	var found []Duplicate
+	for _, ticket := range c.TicketStore.ListByChatID(chatID) {
+		if text.IsReadyStatus(ticket.Status) || !similarWords(words, duplicateWords(ticket.Name)) {
+			continue
+		}
+		found = append(found, Duplicate{Key: ticket.Key, Summary: ticket.Name, Status: ticket.Status})
+		if len(found) == duplicateMax {
+			return found
+		}
+	}
+	if scope != duplicateCheckProject {
+		return found
+	}
+
+	var terms []string
+	for _, w := range duplicateWordRe.FindAllString(strings.ToLower(s), -1) {
+		if len(terms) < duplicateSearchTerms && !slices.Contains(duplicateStopWords, w) && !slices.Contains(terms, w) {
+			terms = append(terms, w)
+		}
+	}
+	jql := fmt.Sprintf(`project = "%s" AND statusCategory != Done AND text ~ "%s" ORDER BY updated DESC`,
+		c.Params.ProjectKey, strings.Join(terms, " "))
+	issues, _, err := c.Jira.SearchIssues(c.Std, jql, duplicateSearchMax, "")
+	if err != nil {
+		c.Log.Warn("Failed to search duplicate issues", "error", err)
+		return found
+	}
+	for _, issue := range issues {
+		if len(found) == duplicateMax {
+			break
+		}
+		if ticket := c.TicketStore.Get(issue.Key); ticket == nil || ticket.ChatID != chatID {
+			continue
+		}
+		if slices.ContainsFunc(found, func(d Duplicate) bool { return d.Key == issue.Key }) {
+			continue
+		}
+		found = append(found, Duplicate{Key: issue.Key, Summary: issue.Summary, Status: issue.Status})
+	}
+	return found
+}
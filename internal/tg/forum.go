@@ -0,0 +1,168 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// The telegram-bot-api library does not know forum topics, so updates are fetched and decoded here
+// to pick up message_thread_id / is_forum, and messages to topics are sent with raw requests.
+
+// incomingUpdate is an update with the forum fields the library drops.
+type incomingUpdate struct {
+	tgbotapi.Update
+	ThreadID int  // forum topic of the message, 0 for General or non-forum chats
+	Forum    bool // the chat is a forum supergroup
+}
+
+// forumUpdate decodes an update together with the forum fields in one pass: the outer fields shadow
+// the library ones of the same JSON name and are copied back by update.
+type forumUpdate struct {
+	tgbotapi.Update
+	Message       *forumMessage `json:"message"`
+	CallbackQuery *struct {
+		tgbotapi.CallbackQuery
+		Message *forumMessage `json:"message"`
+	} `json:"callback_query"`
+}
+
+type forumMessage struct {
+	tgbotapi.Message
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+	Chat            *struct {
+		tgbotapi.Chat
+		IsForum bool `json:"is_forum"`
+	} `json:"chat"`
+}
+
+func (m *forumMessage) message() *tgbotapi.Message {
+	if m.Chat != nil {
+		m.Message.Chat = &m.Chat.Chat
+	}
+	return &m.Message
+}
+
+// update returns the library update with the forum fields of its message.
+func (u *forumUpdate) update() incomingUpdate {
+	out := incomingUpdate{Update: u.Update}
+	var msg *forumMessage
+	if u.Message != nil {
+		msg = u.Message
+		out.Update.Message = msg.message()
+	}
+	if u.CallbackQuery != nil {
+		cb := u.CallbackQuery.CallbackQuery
+		if u.CallbackQuery.Message != nil {
+			msg = u.CallbackQuery.Message
+			cb.Message = msg.message()
+		}
+		out.Update.CallbackQuery = &cb
+	}
+	if msg == nil {
+		return out
+	}
+	out.Forum = msg.Chat != nil && msg.Chat.IsForum
+	if msg.IsTopicMessage {
+		out.ThreadID = msg.MessageThreadID
+	}
+	// Every message in a topic "replies" to the topic's root message; that is not a real reply.
+	if m := out.Update.Message; m != nil && out.ThreadID != 0 && m.ReplyToMessage != nil && m.ReplyToMessage.MessageID == out.ThreadID {
+		m.ReplyToMessage = nil
+	}
+	return out
+}
+
+// pollUpdates long-polls getUpdates and delivers updates to out until ctx is cancelled.
+func (b *Bot) pollUpdates(ctx context.Context, out chan<- incomingUpdate) {
+	offset := b.updCfg.Offset
+	for ctx.Err() == nil {
+		updates, err := b.getUpdates(offset)
+		if err != nil {
+			b.log.Error("Failed to get updates, retrying in 3 seconds", "err", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(3 * time.Second):
+			}
+			continue
+		}
+		for _, upd := range updates {
+			if upd.UpdateID >= offset {
+				offset = upd.UpdateID + 1
+			}
+			select {
+			case out <- upd:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (b *Bot) getUpdates(offset int) ([]incomingUpdate, error) {
+	params := tgbotapi.Params{}
+	params.AddNonZero("offset", offset)
+	params.AddNonZero("limit", 100)
+	params.AddNonZero("timeout", b.updCfg.Timeout)
+	resp, err := b.api.MakeRequest("getUpdates", params)
+	if err != nil {
+		return nil, err
+	}
+	var updates []forumUpdate
+	if err := json.Unmarshal(resp.Result, &updates); err != nil {
+		return nil, err
+	}
+	out := make([]incomingUpdate, len(updates))
+	for i := range updates {
+		out[i] = updates[i].update()
+	}
+	return out, nil
+}
+
+// sendMessage sends msg into the forum topic threadID (0 — General or a regular chat).
+func sendMessage(api *tgbotapi.BotAPI, msg tgbotapi.MessageConfig, threadID int) (tgbotapi.Message, error) {
+	if threadID == 0 {
+		return api.Send(msg)
+	}
+	params := tgbotapi.Params{}
+	params["chat_id"] = strconv.FormatInt(msg.ChatID, 10)
+	params.AddNonZero("message_thread_id", threadID)
+	params["text"] = msg.Text
+	params.AddNonEmpty("parse_mode", msg.ParseMode)
+	params.AddBool("disable_web_page_preview", msg.DisableWebPagePreview)
+	params.AddBool("disable_notification", msg.DisableNotification)
+	params.AddNonZero("reply_to_message_id", msg.ReplyToMessageID)
+	params.AddBool("allow_sending_without_reply", msg.AllowSendingWithoutReply)
+	if err := params.AddInterface("reply_markup", msg.ReplyMarkup); err != nil {
+		return tgbotapi.Message{}, err
+	}
+	resp, err := api.MakeRequest("sendMessage", params)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+	var sent tgbotapi.Message
+	err = json.Unmarshal(resp.Result, &sent)
+	return sent, err
+}
+
+// createForumTopic creates a topic in a forum chat and returns its thread ID.
+func createForumTopic(api *tgbotapi.BotAPI, chatID int64, name string) (int, error) {
+	if runes := []rune(name); len(runes) > 128 {
+		name = string(runes[:127]) + "…"
+	}
+	params := tgbotapi.Params{"chat_id": strconv.FormatInt(chatID, 10), "name": name}
+	resp, err := api.MakeRequest("createForumTopic", params)
+	if err != nil {
+		return 0, err
+	}
+	var topic struct {
+		MessageThreadID int `json:"message_thread_id"`
+	}
+	err = json.Unmarshal(resp.Result, &topic)
+	return topic.MessageThreadID, err
+}
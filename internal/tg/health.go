@@ -0,0 +1,192 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// healthState tracks liveness signals of background loops.
+type healthState struct {
+	mu           sync.Mutex
+	startedAt    time.Time
+	lastPollAt   time.Time
+	lastUpdateAt time.Time
+	storageErr   error
+	storageAt    time.Time
+	getMe        *getMeCall
+}
+
+func (h *healthState) pollDone() {
+	h.mu.Lock()
+	h.lastPollAt = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *healthState) updateProcessed() {
+	h.mu.Lock()
+	h.lastUpdateAt = time.Now()
+	h.mu.Unlock()
+}
+
+// storageResult records the outcome of the last read or write of persisted state.
+func (h *healthState) storageResult(err error) {
+	h.mu.Lock()
+	h.storageErr = err
+	h.storageAt = time.Now()
+	h.mu.Unlock()
+}
+
+// HealthCheck is a single dependency check in a health report.
+type HealthCheck struct {
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// HealthReport is the JSON body of /healthz and /readyz.
+type HealthReport struct {
+	Status                 string                 `json:"status"`
+	Checks                 map[string]HealthCheck `json:"checks"`
+	LastPollAt             *time.Time             `json:"last_poll_at,omitempty"`
+	SecondsSinceLastPoll   *float64               `json:"seconds_since_last_poll,omitempty"`
+	LastUpdateAt           *time.Time             `json:"last_update_at,omitempty"`
+	SecondsSinceLastUpdate *float64               `json:"seconds_since_last_update,omitempty"`
+}
+
+// HealthHandler serves liveness: the poll loop is making progress.
+func (b *Bot) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := b.healthReport()
+		report.Checks["poll"] = b.checkPoll()
+		writeHealth(w, report)
+	})
+}
+
+// ReadyHandler serves readiness: Telegram and Jira are reachable with valid credentials,
+// persisted state is healthy and the poll loop is making progress.
+func (b *Bot) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+		report := b.healthReport()
+		report.Checks["poll"] = b.checkPoll()
+		report.Checks["telegram"] = timedCheck(func() error {
+			return b.checkTelegram(ctx)
+		})
+		report.Checks["jira"] = timedCheck(func() error {
+			_, err := b.jira.Myself(ctx)
+			return err
+		})
+		report.Checks["storage"] = b.checkStorage()
+		writeHealth(w, report)
+	})
+}
+
+// getMeCall is a Telegram getMe call in flight.
+type getMeCall struct {
+	done chan struct{}
+	err  error
+}
+
+// checkTelegram calls getMe, giving up when ctx is done. The library call has no deadline, so a hung
+// call is shared by later checks instead of piling up.
+func (b *Bot) checkTelegram(ctx context.Context) error {
+	b.health.mu.Lock()
+	call := b.health.getMe
+	if call == nil {
+		call = &getMeCall{done: make(chan struct{})}
+		b.health.getMe = call
+		go func() {
+			_, call.err = b.api.GetMe()
+			b.health.mu.Lock()
+			b.health.getMe = nil
+			b.health.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	b.health.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bot) healthReport() HealthReport {
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+	report := HealthReport{Checks: map[string]HealthCheck{}}
+	if !b.health.lastPollAt.IsZero() {
+		t, since := b.health.lastPollAt, time.Since(b.health.lastPollAt).Seconds()
+		report.LastPollAt, report.SecondsSinceLastPoll = &t, &since
+	}
+	if !b.health.lastUpdateAt.IsZero() {
+		t, since := b.health.lastUpdateAt, time.Since(b.health.lastUpdateAt).Seconds()
+		report.LastUpdateAt, report.SecondsSinceLastUpdate = &t, &since
+	}
+	return report
+}
+
+// checkPoll fails when no poll cycle finished within three intervals (at least a minute).
+func (b *Bot) checkPoll() HealthCheck {
+	limit := 3 * time.Duration(b.cfg.BotPollProcessInterval) * time.Second
+	if limit < time.Minute {
+		limit = time.Minute
+	}
+	b.health.mu.Lock()
+	last := b.health.lastPollAt
+	if last.IsZero() {
+		last = b.health.startedAt
+	}
+	b.health.mu.Unlock()
+	if since := time.Since(last); since > limit {
+		return HealthCheck{Error: "no poll cycle for " + since.Round(time.Second).String()}
+	}
+	return HealthCheck{OK: true}
+}
+
+func (b *Bot) checkStorage() HealthCheck {
+	if b.cfg.TicketStorage == storageAggregate && b.cfg.AggregateIssueKey == "" {
+		return HealthCheck{OK: true, Detail: "in-memory only"}
+	}
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+	if b.health.storageErr != nil {
+		return HealthCheck{Error: b.health.storageErr.Error(), Detail: "since " + b.health.storageAt.Format(time.RFC3339)}
+	}
+	if b.cfg.TicketStorage == storageAggregate {
+		return HealthCheck{OK: true, Detail: "aggregate issue " + b.cfg.AggregateIssueKey}
+	}
+	return HealthCheck{OK: true, Detail: "issue properties"}
+}
+
+func timedCheck(fn func() error) HealthCheck {
+	start := time.Now()
+	err := fn()
+	check := HealthCheck{OK: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func writeHealth(w http.ResponseWriter, report HealthReport) {
+	report.Status = "ok"
+	code := http.StatusOK
+	for _, check := range report.Checks {
+		if !check.OK {
+			report.Status = "degraded"
+			code = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
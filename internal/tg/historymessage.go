@@ -1,10 +1,21 @@
 package tg
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// historyKey scopes history to a chat and a forum topic (0 — General or a regular chat).
+type historyKey struct {
+	chatID   int64
+	threadID int
+}
 
 type HistoryMessages struct {
-	historyMapByChatId map[int64][]tgbotapi.Message
-	limit              int
+	mu            sync.Mutex
+	historyByChat map[historyKey][]tgbotapi.Message
+	limit         int
 }
 
 func NewHistoryMessages(limit int) *HistoryMessages {
@@ -12,26 +23,30 @@ func NewHistoryMessages(limit int) *HistoryMessages {
 		limit = 10
 	}
 	return &HistoryMessages{
-		historyMapByChatId: make(map[int64][]tgbotapi.Message),
-		limit:              limit,
+		historyByChat: make(map[historyKey][]tgbotapi.Message),
+		limit:         limit,
 	}
 }
 
-func (h *HistoryMessages) AddMessage(message *tgbotapi.Message) {
-    if h == nil || message == nil || message.Chat == nil {
-        return
-    }
-    chatID := message.Chat.ID
-    messages := append(h.historyMapByChatId[chatID], *message)
-    if h.limit > 0 && len(messages) > h.limit {
-        messages = messages[1:]
-    }
-    h.historyMapByChatId[chatID] = messages
+func (h *HistoryMessages) AddMessage(message *tgbotapi.Message, threadID int) {
+	if h == nil || message == nil || message.Chat == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := historyKey{chatID: message.Chat.ID, threadID: threadID}
+	messages := append(h.historyByChat[key], *message)
+	if h.limit > 0 && len(messages) > h.limit {
+		messages = messages[1:]
+	}
+	h.historyByChat[key] = messages
 }
 
-func (h *HistoryMessages) GetMessages(chatId int64) []tgbotapi.Message {
-    if h == nil {
-        return nil
-    }
-    return h.historyMapByChatId[chatId]
+func (h *HistoryMessages) GetMessages(chatId int64, threadID int) []tgbotapi.Message {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]tgbotapi.Message(nil), h.historyByChat[historyKey{chatID: chatId, threadID: threadID}]...)
 }
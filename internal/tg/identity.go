@@ -0,0 +1,285 @@
+package tg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/store"
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// identityProperty is the project entity property holding confirmed Telegram-to-Jira links.
+	identityProperty        = "telegram-bot-users"
+	identityPropertyVersion = 1
+
+	linkCodeTTL         = 15 * time.Minute
+	linkCodeMaxAttempts = 5
+	// linkCodeCooldown is the minimum time between codes requested by a Telegram user or mailed to
+	// a Jira account.
+	linkCodeCooldown = 5 * time.Minute
+)
+
+var (
+	// ErrLinkNotConfigured means there is no issue to send confirmation codes through.
+	ErrLinkNotConfigured = errors.New("jira link: no issue to send confirmation codes")
+	// ErrLinkUserNotFound means the email does not match exactly one Jira account.
+	ErrLinkUserNotFound = errors.New("jira link: account not found")
+	// ErrLinkTooSoon means a code was requested recently or too many wrong codes were sent.
+	ErrLinkTooSoon = errors.New("jira link: too many requests")
+)
+
+type identityPropertyValue struct {
+	Version int              `json:"version"`
+	Links   []store.JiraLink `json:"links"`
+	Private []int64          `json:"private_notify,omitempty"` // users notified in the private chat
+}
+
+// identities resolves Telegram users to Jira accounts: JIRA_USER_MAP first, then links
+// confirmed by the users themselves.
+type identities struct {
+	static jiraUserMap
+	links  *store.IdentityStore
+
+	mu      sync.Mutex
+	pending map[int64]*linkRequest // by Telegram user ID
+	mailed  map[string]time.Time   // last code mailed, by Jira account ID
+}
+
+// linkRequest is a /link_jira waiting for the code mailed to the Jira account. Wrong codes are
+// counted until the request expires, across repeated requests.
+type linkRequest struct {
+	account  jira.User
+	code     string
+	sent     time.Time
+	expires  time.Time
+	attempts int
+}
+
+func newIdentities(static jiraUserMap) *identities {
+	return &identities{static: static, links: store.NewIdentityStore(), pending: make(map[int64]*linkRequest), mailed: make(map[string]time.Time)}
+}
+
+// pruneLinks drops expired link requests and cooldowns; requires mu.
+func (i *identities) pruneLinks(now time.Time) {
+	for id, req := range i.pending {
+		if now.After(req.expires) {
+			delete(i.pending, id)
+		}
+	}
+	for account, sent := range i.mailed {
+		if now.Sub(sent) >= linkCodeCooldown {
+			delete(i.mailed, account)
+		}
+	}
+}
+
+func (i *identities) empty() bool {
+	return i.static.empty() && len(i.links.List()) == 0
+}
+
+// account returns the Jira account of the Telegram user, "" if the user is not mapped.
+func (i *identities) account(user TelegramUser) string {
+	if account := i.static.account(user); account != "" {
+		return account
+	}
+	return i.links.Account(user)
+}
+
+// user returns the Telegram user mapped to the Jira account.
+func (i *identities) user(account string) (TelegramUser, bool) {
+	if user, ok := i.static.user(account); ok {
+		return user, true
+	}
+	return i.links.User(account)
+}
+
+// users returns the Telegram users mapped to any of the accounts.
+func (i *identities) users(accounts []string) []TelegramUser {
+	var users []TelegramUser
+	for _, account := range accounts {
+		if user, ok := i.user(account); ok {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// loadIdentities reads confirmed links from the project property.
+func (b *Bot) loadIdentities(ctx context.Context) error {
+	raw, err := b.jira.GetProjectProperty(ctx, identityProperty)
+	if errors.Is(err, jira.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var value identityPropertyValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("identity links: %w", err)
+	}
+	if value.Version > identityPropertyVersion {
+		return fmt.Errorf("identity links version %d is newer than supported %d", value.Version, identityPropertyVersion)
+	}
+	b.identities.links.Init(value.Links, value.Private)
+	b.log.Info("Load jira user links", slog.Int("size", len(value.Links)), slog.Int("private_notify", len(value.Private)))
+	return nil
+}
+
+// saveIdentities persists links if they changed since the previous call.
+func (b *Bot) saveIdentities(ctx context.Context) {
+	if !b.identities.links.DirtyAndReset() {
+		return
+	}
+	value := identityPropertyValue{Version: identityPropertyVersion, Links: b.identities.links.List(), Private: b.identities.links.PrivateUsers()}
+	if err := b.jira.SetProjectProperty(ctx, identityProperty, value); err != nil {
+		b.identities.links.MarkDirty() // retry on the next poll cycle
+		b.log.Error("Failed to save jira user links", slog.Any("err", err))
+	}
+}
+
+// JiraAccount returns the Jira account linked to the Telegram user, "" if there is none.
+func (c *Ctx) JiraAccount(user TelegramUser) string {
+	return c.Params.identities.account(user)
+}
+
+// JiraLink returns the link the user confirmed with /link_jira.
+func (c *Ctx) JiraLink(user TelegramUser) (store.JiraLink, bool) {
+	return c.Params.identities.links.Link(user)
+}
+
+// JiraAuthor names the author of a comment posted to Jira: a mention of the linked account
+// or, for users without one, the Telegram name.
+func (c *Ctx) JiraAuthor(user *tgbotapi.User) string {
+	if account := c.JiraAccount(TelegramUserOf(user)); account != "" {
+		return jira.MentionMarkup(account)
+	}
+	return text.BuildFullNameUser(user)
+}
+
+var usernameMentionRe = regexp.MustCompile(`@(\w{4,32})`)
+
+// JiraMentions replaces @username mentions of linked users with Jira account mentions. Mention
+// markup typed by the user is escaped, so only linked accounts can be mentioned.
+func (c *Ctx) JiraMentions(s string) string {
+	return usernameMentionRe.ReplaceAllStringFunc(jira.EscapeMentionMarkup(s), func(m string) string {
+		if account := c.JiraAccount(TelegramUser{Username: m[1:]}); account != "" {
+			return jira.MentionMarkup(account)
+		}
+		return m
+	})
+}
+
+// RequestJiraLink starts linking the current user to the Jira account with the email: a one-time
+// code is mailed to the account through a Jira notification, so it only reaches the account owner.
+// A user and an account get a code at most once per linkCodeCooldown, and a user who sent too many
+// wrong codes waits until the request expires.
+func (c *Ctx) RequestJiraLink(email string) (*jira.User, error) {
+	user := c.Tg.CurrentUser()
+	if user == nil {
+		return nil, ErrLinkUserNotFound
+	}
+	if c.Params.linkIssueKey == "" {
+		return nil, ErrLinkNotConfigured
+	}
+	ids := c.Params.identities
+	now := time.Now()
+	ids.mu.Lock()
+	ids.pruneLinks(now)
+	if req := ids.pending[user.ID]; req != nil && (now.Sub(req.sent) < linkCodeCooldown || req.attempts >= linkCodeMaxAttempts) {
+		ids.mu.Unlock()
+		return nil, ErrLinkTooSoon
+	}
+	ids.mu.Unlock()
+
+	found, err := c.Jira.FindUsers(c.Std, email)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != 1 {
+		return nil, ErrLinkUserNotFound
+	}
+	account := found[0]
+	ids.mu.Lock()
+	if _, ok := ids.mailed[account.AccountID]; ok {
+		ids.mu.Unlock()
+		return nil, ErrLinkTooSoon
+	}
+	ids.mailed[account.AccountID] = now
+	ids.mu.Unlock()
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return nil, err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	lang := c.Lang()
+	err = c.Jira.NotifyUsers(c.Std, c.Params.linkIssueKey, text.TextJiraLinkSubject(lang),
+		text.TextJiraLinkBody(lang, text.BuildFullNameUser(user), code), []string{account.AccountID})
+	if err != nil {
+		ids.mu.Lock()
+		delete(ids.mailed, account.AccountID)
+		ids.mu.Unlock()
+		return nil, err
+	}
+	account.Email = strings.TrimSpace(email)
+
+	ids.mu.Lock()
+	req := &linkRequest{account: account, code: code, sent: now, expires: now.Add(linkCodeTTL)}
+	if old := ids.pending[user.ID]; old != nil {
+		req.attempts = old.attempts
+	}
+	ids.pending[user.ID] = req
+	ids.mu.Unlock()
+	return &account, nil
+}
+
+// ConfirmJiraLink completes linking with the mailed code. Requests expire after linkCodeTTL
+// and accept no code after too many wrong ones.
+func (c *Ctx) ConfirmJiraLink(code string) (store.JiraLink, bool) {
+	user := c.Tg.CurrentUser()
+	if user == nil {
+		return store.JiraLink{}, false
+	}
+	ids := c.Params.identities
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+	req := ids.pending[user.ID]
+	if req == nil || time.Now().After(req.expires) {
+		delete(ids.pending, user.ID)
+		return store.JiraLink{}, false
+	}
+	if req.attempts >= linkCodeMaxAttempts || req.code != strings.TrimSpace(code) {
+		req.attempts++
+		return store.JiraLink{}, false
+	}
+	delete(ids.pending, user.ID)
+	link := store.JiraLink{
+		User:      TelegramUserOf(user),
+		AccountID: req.account.AccountID,
+		Name:      req.account.DisplayName,
+		Email:     req.account.Email,
+		LinkedAt:  time.Now().UTC(),
+	}
+	ids.links.Add(link)
+	c.Log.Info("Jira account linked", "user", user.ID, "account", link.AccountID)
+	return link, true
+}
+
+// UnlinkJira removes the link of the current user; reports whether there was one.
+func (c *Ctx) UnlinkJira() bool {
+	user := c.Tg.CurrentUser()
+	return user != nil && c.Params.identities.links.Remove(TelegramUserOf(user))
+}
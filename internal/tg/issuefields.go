@@ -0,0 +1,324 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"telegram-bot-jira/internal/config"
+	"telegram-bot-jira/internal/jira"
+)
+
+// summaryMaxLen is the length limit of Jira issue summaries.
+const summaryMaxLen = 255
+
+// IssueFieldsData is the data of field templates in the chat create settings.
+type IssueFieldsData struct {
+	Chat    string // chat title
+	ChatID  int64
+	Creator TelegramUser
+	Text    string            // command text without key=value arguments; for the summary, the first history message if empty
+	Args    map[string]string // key=value arguments of the command
+}
+
+// issueTemplate is the compiled create settings of a chat: Jira field values by field ID
+// whose strings are templates.
+type issueTemplate struct {
+	summary  *template.Template // nil: the text is the summary
+	fields   map[string]any
+	dueDays  int
+	reporter bool
+	args     bool // the templates use key=value arguments of the command
+}
+
+// fieldsAlwaysSet are filled by the bot itself and need no settings.
+var fieldsAlwaysSet = []string{"project", "issuetype", "summary", "description", "reporter", "labels"}
+
+// loadIssueTemplates compiles the create settings of all chats. Field names are resolved to IDs and
+// constant option values are checked against the create screen; if it cannot be fetched, settings are
+// used as written.
+func (b *Bot) loadIssueTemplates(ctx context.Context) (map[int64]*issueTemplate, error) {
+	templates := make(map[int64]*issueTemplate)
+	var meta []jira.FieldMeta
+	metaLoaded := false
+	for chatID, chat := range b.cfg.Chats {
+		if chat.Create.Empty() && chat.Create.Reporter == nil {
+			continue
+		}
+		if !metaLoaded {
+			metaLoaded = true
+			var err error
+			if meta, err = b.jira.CreateMeta(ctx); err != nil {
+				b.log.Warn("Failed to get issue create meta, chat create settings are not validated", "err", err)
+			}
+		}
+		t, err := compileIssueTemplate(chat.Create, meta)
+		if err != nil {
+			return nil, fmt.Errorf("chat %d: %w", chatID, err)
+		}
+		templates[chatID] = t
+	}
+	for _, f := range meta {
+		if f.Required && !f.HasDefault && !slices.Contains(fieldsAlwaysSet, f.FieldID) {
+			b.log.Warn("Required Jira field is only set in chats with create settings", "field", f.FieldID, "name", f.Name)
+		}
+	}
+	return templates, nil
+}
+
+func compileIssueTemplate(s config.CreateSettings, meta []jira.FieldMeta) (*issueTemplate, error) {
+	raw := maps.Clone(s.Fields)
+	if raw == nil {
+		raw = make(map[string]any)
+	}
+	if len(s.Labels) > 0 {
+		labels := []any{"telegram"}
+		for _, label := range s.Labels {
+			labels = append(labels, label)
+		}
+		raw["labels"] = labels
+	}
+	if len(s.Components) > 0 {
+		var components []any
+		for _, name := range s.Components {
+			components = append(components, map[string]any{"name": name})
+		}
+		raw["components"] = components
+	}
+	if s.Priority != "" {
+		raw["priority"] = map[string]any{"name": s.Priority}
+	}
+	if s.DueDays > 0 {
+		raw["duedate"] = nil // checked below, the date is set when the issue is created
+	}
+
+	t := &issueTemplate{fields: make(map[string]any, len(raw)), dueDays: s.DueDays, reporter: s.Reporter == nil || *s.Reporter}
+	for name, value := range raw {
+		id := name
+		if meta != nil {
+			i := slices.IndexFunc(meta, func(f jira.FieldMeta) bool { return f.FieldID == name || strings.EqualFold(f.Name, name) })
+			if i < 0 {
+				return nil, fmt.Errorf("field %q is not on the create screen", name)
+			}
+			id = meta[i].FieldID
+			if err := checkAllowedValues(meta[i], value); err != nil {
+				return nil, err
+			}
+		}
+		if value == nil {
+			continue
+		}
+		compiled, err := compileFieldValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", name, err)
+		}
+		t.fields[id] = compiled
+	}
+	if s.Summary != "" {
+		var err error
+		if t.summary, err = template.New("summary").Option("missingkey=zero").Parse(s.Summary); err != nil {
+			return nil, fmt.Errorf("summary: %w", err)
+		}
+	}
+	if encoded, err := json.Marshal(raw); err == nil {
+		t.args = strings.Contains(string(encoded), ".Args") || strings.Contains(s.Summary, ".Args")
+	}
+	sample := IssueFieldsData{Chat: "chat", Creator: TelegramUser{ID: 1, Username: "user", Name: "User"}, Args: map[string]string{}}
+	if _, err := t.render(sample, time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := t.renderSummary(sample); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// renderSummary executes the summary template; without one the summary is the text.
+func (t *issueTemplate) renderSummary(data IssueFieldsData) (string, error) {
+	if t.summary == nil {
+		return data.Text, nil
+	}
+	var sb strings.Builder
+	if err := t.summary.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("summary: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// compileFieldValue parses strings of a JSON value as templates.
+func compileFieldValue(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		return template.New("field").Option("missingkey=zero").Parse(v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			compiled, err := compileFieldValue(child)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = compiled
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, child := range v {
+			compiled, err := compileFieldValue(child)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, compiled)
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// checkAllowedValues checks constant ids, names and values of options against the field's allowed values.
+func checkAllowedValues(f jira.FieldMeta, value any) error {
+	if len(f.AllowedValues) == 0 {
+		return nil
+	}
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if err := checkAllowedValues(f, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, attr := range []string{"id", "name", "value"} {
+			s, ok := v[attr].(string)
+			if !ok || strings.Contains(s, "{{") {
+				continue
+			}
+			allowed := slices.ContainsFunc(f.AllowedValues, func(a jira.AllowedValue) bool {
+				return attr == "id" && a.ID == s || attr == "name" && strings.EqualFold(a.Name, s) || attr == "value" && strings.EqualFold(a.Value, s)
+			})
+			if !allowed {
+				return fmt.Errorf("%q is not an allowed value of field %q", s, f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// render executes the templates; fields, list items and attributes that render empty are left out.
+func (t *issueTemplate) render(data IssueFieldsData, now time.Time) (map[string]any, error) {
+	fields := make(map[string]any, len(t.fields)+1)
+	for id, value := range t.fields {
+		rendered, empty, err := renderFieldValue(value, data)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", id, err)
+		}
+		if empty {
+			continue
+		}
+		if labels, ok := rendered.([]any); ok && id == "labels" {
+			// Jira labels cannot contain spaces, e.g. a label made of the chat title.
+			for i, label := range labels {
+				labels[i] = strings.Join(strings.Fields(fmt.Sprint(label)), "_")
+			}
+		}
+		fields[id] = rendered
+	}
+	if t.dueDays > 0 {
+		fields["duedate"] = now.AddDate(0, 0, t.dueDays).Format(time.DateOnly)
+	}
+	return fields, nil
+}
+
+func renderFieldValue(value any, data IssueFieldsData) (any, bool, error) {
+	switch v := value.(type) {
+	case *template.Template:
+		var sb strings.Builder
+		if err := v.Execute(&sb, data); err != nil {
+			return nil, true, err
+		}
+		s := strings.TrimSpace(sb.String())
+		return s, s == "", nil
+	case string:
+		return v, v == "", nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			rendered, empty, err := renderFieldValue(child, data)
+			if err != nil {
+				return nil, true, err
+			}
+			if !empty {
+				out[k] = rendered
+			}
+		}
+		return out, len(out) == 0 && len(v) > 0, nil
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, child := range v {
+			rendered, empty, err := renderFieldValue(child, data)
+			if err != nil {
+				return nil, true, err
+			}
+			if !empty {
+				out = append(out, rendered)
+			}
+		}
+		return out, len(out) == 0 && len(v) > 0, nil
+	default:
+		return v, false, nil
+	}
+}
+
+// TakesCreateArgs reports whether the chat's create settings use key=value arguments of
+// /create_issue; elsewhere such words are part of the ticket name.
+func (p CtxParams) TakesCreateArgs(chatID int64) bool {
+	t := p.issueTemplates[chatID]
+	return t != nil && t.args
+}
+
+// IssueFields returns the fields of an issue created in the chat besides summary and description:
+// the chat create settings and the reporter linked to the creator.
+func (c *Ctx) IssueFields(data IssueFieldsData) (map[string]any, error) {
+	fields := make(map[string]any)
+	reporter := true
+	if t := c.Params.issueTemplates[data.ChatID]; t != nil {
+		var err error
+		if fields, err = t.render(data, time.Now()); err != nil {
+			return nil, err
+		}
+		reporter = t.reporter
+	}
+	if account := c.JiraAccount(data.Creator); reporter && account != "" {
+		fields["reporter"] = map[string]any{"id": account}
+	}
+	return fields, nil
+}
+
+// IssueSummary returns the summary of an issue created in the chat: the chat summary template or the
+// text itself, on one line and truncated to the Jira limit. Empty if there is nothing to make it of:
+// without text the template is not rendered, so the caller's fallback applies instead of e.g. "[chat]".
+func (c *Ctx) IssueSummary(data IssueFieldsData) (string, error) {
+	if strings.TrimSpace(data.Text) == "" {
+		return "", nil
+	}
+	summary := data.Text
+	if t := c.Params.issueTemplates[data.ChatID]; t != nil {
+		var err error
+		if summary, err = t.renderSummary(data); err != nil {
+			return "", err
+		}
+	}
+	summary = strings.Join(strings.Fields(summary), " ")
+	if runes := []rune(summary); len(runes) > summaryMaxLen {
+		summary = strings.TrimSpace(string(runes[:summaryMaxLen-1])) + "…"
+	}
+	return summary, nil
+}
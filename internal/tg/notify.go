@@ -0,0 +1,128 @@
+package tg
+
+import (
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// issueChange is the difference between the stored ticket and the fetched issue.
+type issueChange struct {
+	fromStatus, toStatus     string
+	fromAssignee, toAssignee string
+	toAssigneeAccount        string
+	assigneeSeen             bool // fromAssignee was recorded, see CreatedTicket.AssigneeSeen
+}
+
+func diffIssue(ticket *CreatedTicket, issue *jira.IssueStatus) issueChange {
+	return issueChange{
+		fromStatus:        ticket.Status,
+		toStatus:          issue.Status,
+		fromAssignee:      ticket.Assignee,
+		toAssignee:        strings.TrimSpace(issue.Assignee),
+		toAssigneeAccount: issue.AssigneeAccountID,
+		assigneeSeen:      ticket.AssigneeSeen,
+	}
+}
+
+func (c issueChange) statusChanged() bool   { return c.fromStatus != c.toStatus }
+func (c issueChange) assigneeChanged() bool { return c.fromAssignee != c.toAssignee }
+
+// assigneeAnnounced reports whether the assignee change is real and not the first record of it.
+func (c issueChange) assigneeAnnounced() bool { return c.assigneeSeen && c.assigneeChanged() }
+
+// notifyRules are the notification settings of a chat with NOTIFY_* defaults applied.
+type notifyRules struct {
+	transitions    []string
+	assignee       bool
+	mentionCreator bool
+}
+
+func (b *Bot) notifyRules(chatID int64) notifyRules {
+	rules := notifyRules{
+		transitions:    b.cfg.NotifyTransitions,
+		assignee:       b.cfg.NotifyAssignee,
+		mentionCreator: b.cfg.NotifyMentionCreator,
+	}
+	chat := b.cfg.Chat(chatID).Notify
+	if chat.Transitions != nil {
+		rules.transitions = chat.Transitions
+	}
+	if chat.Assignee != nil {
+		rules.assignee = *chat.Assignee
+	}
+	if chat.MentionCreator != nil {
+		rules.mentionCreator = *chat.MentionCreator
+	}
+	return rules
+}
+
+// notifiesTransition reports whether a rule "To" or "From->To" matches the transition.
+func (r notifyRules) notifiesTransition(from, to string) bool {
+	for _, rule := range r.transitions {
+		fromRule, toRule, ok := strings.Cut(rule, "->")
+		if !ok {
+			fromRule, toRule = "*", rule
+		}
+		if statusMatches(fromRule, from) && statusMatches(toRule, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusMatches compares a status with a rule pattern: a status name, "*" or "ready".
+func statusMatches(pattern, status string) bool {
+	pattern = strings.TrimSpace(pattern)
+	switch {
+	case pattern == "*":
+		return true
+	case strings.EqualFold(pattern, "ready") && text.IsReadyStatus(status):
+		return true
+	default:
+		return strings.EqualFold(pattern, strings.TrimSpace(status))
+	}
+}
+
+// notifyChange announces a status or assignee change in the ticket chat according to the chat rules.
+// Closing transitions keep their own message with the reopen button.
+func (b *Bot) notifyChange(ticket *CreatedTicket, change issueChange) {
+	if change.fromStatus == "" {
+		// The ticket is seen for the first time, there is nothing to compare with.
+		return
+	}
+	rules := b.notifyRules(ticket.ChatID)
+	notifyStatus := change.statusChanged() && rules.notifiesTransition(change.fromStatus, change.toStatus)
+	notifyAssignee := change.assigneeAnnounced() && rules.assignee
+	mentions := recipients(ticket, rules.mentionCreator)
+	if change.assigneeAnnounced() {
+		// A new assignee with a linked account learns about the ticket in Telegram.
+		if user, ok := b.identities.user(change.toAssigneeAccount); ok {
+			mentions = append(mentions, user)
+		}
+	}
+
+	if notifyStatus && text.IsReadyStatus(change.toStatus) {
+		checkTicketIsClosing(b, ticket, mentions)
+		notifyStatus = false
+	}
+	if !notifyStatus && !notifyAssignee {
+		return
+	}
+	data := text.TicketChangedData{Key: ticket.Key, URL: b.jira.BrowseURL(ticket.Key)}
+	if notifyStatus {
+		data.FromStatus, data.ToStatus = change.fromStatus, change.toStatus
+	}
+	if notifyAssignee {
+		data.AssigneeChanged = true
+		data.FromAssignee, data.ToAssignee = change.fromAssignee, change.toAssignee
+	}
+	msg := tgbotapi.NewMessage(ticket.ChatID, text.TextTicketChangedHTML(b.chatLang(ticket.ChatID), data, mentions))
+	msg.ParseMode = tgbotapi.ModeHTML
+	if err := b.sendTicketNotification(ticket, msg); err != nil {
+		b.log.Error("Failed to notify ticket change", "key", ticket.Key, "error", err)
+	}
+}
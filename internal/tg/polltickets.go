@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/metrics"
 	"telegram-bot-jira/internal/text"
 	"time"
 
@@ -17,47 +18,74 @@ func (b *Bot) pollTickets(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
+		case req := <-b.resyncs:
+			req.done <- b.resyncTicket(ctx, req.key)
 		case <-ticker.C:
+			start := time.Now()
 			tickets := b.ticketStore.ListAll()
 			for i := range tickets {
-				ticket := &tickets[i]
-				processComments(ctx, b, ticket)
-				processCheckStatus(b, ctx, ticket)
-			}
-			// periodic sync of aggregate issue only if data changed
-			if b.ticketStore.DirtyAndReset() {
-				b.updateAggregateToJira(ctx)
+				b.pollTicket(ctx, &tickets[i])
 			}
+			b.pruneCards()
+			b.pruneWatchersSynced()
+			metrics.PollDuration.Observe(time.Since(start).Seconds())
+			b.health.pollDone()
+			b.updateTicketMetrics()
+			// persist only changed tickets
+			b.saveTickets(ctx)
+			b.saveIdentities(ctx)
 		}
 	}
 }
 
-func processComments(ctx context.Context, b *Bot, ticket *CreatedTicket) {
-	if comments, err := b.jira.GetComments(ctx, ticket.Key); err != nil {
+// pollTicket runs one poll step for the ticket; returns the issue, or nil if it could not be fetched
+// or the ticket was dropped.
+func (b *Bot) pollTicket(ctx context.Context, ticket *CreatedTicket) *jira.IssueStatus {
+	comments := processComments(ctx, b, ticket)
+	issue := processCheckStatus(b, ctx, ticket)
+	if issue != nil {
+		b.syncJiraWatchers(ctx, ticket, issue)
+		b.refreshTicketCard(ticket, issue, comments)
+	}
+	return issue
+}
+
+// processComments forwards new Jira comments to Telegram and returns the comment count, -1 on error.
+func processComments(ctx context.Context, b *Bot, ticket *CreatedTicket) int {
+	comments, err := b.jira.GetComments(ctx, ticket.Key)
+	if err != nil {
 		b.log.Error("Failed get issue comments", "key", ticket.Key, "error", err)
-	} else {
-		newLastCommentAt := ticket.LastCommentAt
-		for i := range comments {
-			comment := &comments[i]
-			if comment.Created.Time.After(ticket.LastCommentAt) {
-				processComment(ctx, b, ticket, comment)
-				if comment.Created.Time.After(newLastCommentAt) {
-					newLastCommentAt = comment.Created.Time
-				}
+		return -1
+	}
+	b.trackFirstResponse(ticket, comments)
+	newLastCommentAt := ticket.LastCommentAt
+	for i := range comments {
+		comment := &comments[i]
+		if comment.Created.Time.After(ticket.LastCommentAt) {
+			processComment(ctx, b, ticket, comment)
+			if comment.Created.Time.After(newLastCommentAt) {
+				newLastCommentAt = comment.Created.Time
 			}
 		}
-		if ticket.LastCommentAt != newLastCommentAt {
-			newLastCommentAt = newLastCommentAt.Add(time.Second)
-			b.ticketStore.UpdateLastCommentAt(ticket.Key, newLastCommentAt)
-		}
 	}
+	if ticket.LastCommentAt != newLastCommentAt {
+		newLastCommentAt = newLastCommentAt.Add(time.Second)
+		b.ticketStore.UpdateLastCommentAt(ticket.Key, newLastCommentAt)
+	}
+	return len(comments)
 }
 
 func processComment(ctx context.Context, b *Bot, ticket *CreatedTicket, comment *jira.Comment) {
+	// Comments the bot posted from Telegram mention linked users; they are not news to the chat.
+	if b.postedComments.has(comment.ID) {
+		return
+	}
 	targetUserName := b.cfg.JiraUserName
 
 	textComment, hasPrefix := strings.CutPrefix(comment.Body.Text, "/tg")
-	if !(hasPrefix || targetUserName != "" && strings.Contains(comment.RenderedBody, targetUserName)) {
+	// Users with linked Jira accounts mentioned in the comment get it in Telegram too.
+	mentioned := b.identities.users(comment.Mentions())
+	if !(hasPrefix || targetUserName != "" && strings.Contains(comment.RenderedBody, targetUserName) || len(mentioned) > 0) {
 		return
 	}
 	textComment = strings.TrimSpace(textComment)
@@ -67,10 +95,10 @@ func processComment(ctx context.Context, b *Bot, ticket *CreatedTicket, comment
 		author = strings.TrimSpace(comment.Author.Email)
 	}
 
-	msgText := text.TextCommentJiraToTelegram(ticket.Key, ticket.CreatorUsername, author, textComment)
+	msgText := text.TextCommentJiraToTelegram(b.chatLang(ticket.ChatID), ticket.Key, append(recipients(ticket, true), mentioned...), author, textComment)
 	msg := tgbotapi.NewMessage(ticket.ChatID, msgText)
 	msg.ParseMode = tgbotapi.ModeHTML
-	if _, err := b.api.Send(msg); err != nil {
+	if err := b.sendTicketNotification(ticket, msg); err != nil {
 		b.log.Error("Failed notify mention", "key", ticket.Key, "error", err)
 		return
 	}
@@ -80,12 +108,15 @@ func processComment(ctx context.Context, b *Bot, ticket *CreatedTicket, comment
 	// }
 }
 
-func processCheckStatus(b *Bot, ctx context.Context, ticket *CreatedTicket) {
+// processCheckStatus syncs the ticket with the issue status; returns the issue, or nil if it
+// could not be fetched or the ticket was dropped.
+func processCheckStatus(b *Bot, ctx context.Context, ticket *CreatedTicket) *jira.IssueStatus {
 	ticketActual, err := b.jira.GetIssueStatus(ctx, ticket.Key)
 	if err != nil {
 		b.log.Info("Failed get issue status", "key", ticket.Key)
-		return
+		return nil
 	}
+	b.trackResolution(ticket, ticketActual)
 	if text.IsReadyStatus(ticketActual.Status) {
 		retentionHours := b.cfg.ClosedTicketTTLHours
 		if retentionHours <= 0 {
@@ -95,29 +126,70 @@ func processCheckStatus(b *Bot, ctx context.Context, ticket *CreatedTicket) {
 		if ticketActual.Updated.Before(expireBefore) {
 			b.log.Info("Removing stale closed ticket", "key", ticketActual.Key, "updated", ticketActual.Updated)
 			b.ticketStore.Delete(ticket.Key)
-			return
+			return nil
 		}
 	}
-	if ticketActual.Status == ticket.Status {
+	b.checkSLA(ticket, ticketActual)
+	syncSummary(b, ticket, ticketActual)
+	change := diffIssue(ticket, ticketActual)
+	if !change.statusChanged() && !change.assigneeChanged() && change.assigneeSeen {
+		return ticketActual
+	}
+	b.log.Info("Issue updated", "key", ticketActual.Key, "status", change.toStatus, "assignee", change.toAssignee)
+	ticket.Status, ticket.Assignee, ticket.AssigneeSeen = change.toStatus, change.toAssignee, true
+	b.notifyChange(ticket, change)
+	b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+		t.Status, t.Assignee, t.AssigneeSeen = change.toStatus, change.toAssignee, true
+		return true
+	})
+	return ticketActual
+}
+
+// syncSummary renames the ticket when its summary is edited in Jira. Tickets stored before summaries
+// were tracked keep their name until the next edit, unless they have none.
+func syncSummary(b *Bot, ticket *CreatedTicket, issue *jira.IssueStatus) {
+	if issue.Summary == "" || issue.Summary == ticket.Summary {
 		return
 	}
-	b.log.Info("Issue status updated", "key", ticketActual.Key, "status", ticketActual.Status)
-	ticket.Status = ticketActual.Status
-	checkTicketIsClosing(b, ticket)
-	b.ticketStore.UpdateStatus(ticket.Key, ticketActual.Status)
+	rename := ticket.Summary != "" || ticket.Name == ""
+	apply := func(t *CreatedTicket) {
+		t.Summary = issue.Summary
+		if rename {
+			t.Name = issue.Summary
+		}
+	}
+	apply(ticket)
+	b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+		apply(t)
+		return true
+	})
 }
 
-func checkTicketIsClosing(b *Bot, ticket *CreatedTicket) {
+// checkTicketIsClosing sends the closing notification with the reopen button, mentioning the users.
+func checkTicketIsClosing(b *Bot, ticket *CreatedTicket, mentions []TelegramUser) {
 	if text.IsReadyStatus(ticket.Status) {
+		lang := b.chatLang(ticket.ChatID)
 		url := b.jira.BrowseURL(ticket.Key)
-		txt := text.TextTicketClosedHTML(ticket.Key, ticket.Status, url, ticket.CreatorUsername)
+		txt := text.TextTicketClosedHTML(lang, ticket.Key, ticket.Status, url, mentions)
 		msg := tgbotapi.NewMessage(ticket.ChatID, txt)
 		if b.cfg.JiraReopenStatus != "" {
-			callbackData := "reopen|" + ticket.Key
-			button := tgbotapi.NewInlineKeyboardButtonData("Переоткрыть", callbackData)
+			callbackData := CallbackReopen + "|" + ticket.Key
+			button := tgbotapi.NewInlineKeyboardButtonData(text.ButtonReopen(lang), callbackData)
 			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(button))
 		}
 		msg.ParseMode = tgbotapi.ModeHTML
-		_, _ = b.api.Send(msg)
+		_ = b.sendTicketNotification(ticket, msg)
+	}
+}
+
+// updateTicketMetrics rebuilds the tickets-per-status gauge from the store.
+func (b *Bot) updateTicketMetrics() {
+	counts := map[string]int{}
+	for _, ticket := range b.ticketStore.ListAll() {
+		counts[ticket.Status]++
+	}
+	metrics.TicketsTracked.Reset()
+	for status, n := range counts {
+		metrics.TicketsTracked.Set(float64(n), status)
 	}
 }
@@ -0,0 +1,87 @@
+package tg
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// IsPrivate reports whether the update comes from a private chat with the bot.
+func (c *Ctx) IsPrivate() bool {
+	chat := c.Tg.CurrentChat()
+	return chat != nil && chat.IsPrivate()
+}
+
+// CanAccessTicket reports whether the ticket may be shown or commented in the current chat:
+// in private chats only tickets created or watched by the user are available.
+func (c *Ctx) CanAccessTicket(key string) bool {
+	if !c.IsPrivate() {
+		return true
+	}
+	ticket := c.TicketStore.Get(key)
+	user := c.Tg.CurrentUser()
+	return ticket != nil && user != nil && (ticket.CreatedBy(user.ID, user.UserName) || ticket.WatchedBy(user.ID, user.UserName))
+}
+
+// ChatTitle returns the title of a chat the bot is in; titles are fetched once and cached.
+func (bot *BotTgAction) ChatTitle(chatID int64) string {
+	return chatTitle(bot.tgApi, bot.chatTitles, chatID)
+}
+
+func chatTitle(api *tgbotapi.BotAPI, cache *sync.Map, chatID int64) string {
+	if title, ok := cache.Load(chatID); ok {
+		return title.(string)
+	}
+	chat, err := api.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: chatID}})
+	if err != nil {
+		return ""
+	}
+	title := chat.Title
+	if title == "" {
+		title = chat.UserName
+	}
+	cache.Store(chatID, title)
+	return title
+}
+
+// NotifiesPrivately reports whether notifications about the user's tickets go to the private chat.
+func (c *Ctx) NotifiesPrivately(userID int64) bool {
+	return c.Params.identities.links.NotifiesPrivately(userID)
+}
+
+// SetNotifyPrivately switches notifications about the user's tickets to the private chat or back.
+func (c *Ctx) SetNotifyPrivately(userID int64, private bool) {
+	c.Params.identities.links.SetNotifyPrivately(userID, private)
+}
+
+// privateChatID returns the private chat of the ticket creator if they opted in to private
+// notifications, 0 otherwise. A private chat has the ID of the user.
+func (b *Bot) privateChatID(ticket *CreatedTicket) int64 {
+	if b.identities.links.NotifiesPrivately(ticket.Creator.ID) {
+		return ticket.Creator.ID
+	}
+	return 0
+}
+
+// migratePrivateChats moves the private notification opt-in stored on tickets by earlier versions
+// to their creators.
+func (b *Bot) migratePrivateChats() {
+	for _, ticket := range b.ticketStore.ListAll() {
+		if ticket.PrivateChatID == 0 {
+			continue
+		}
+		b.identities.links.SetNotifyPrivately(ticket.PrivateChatID, true)
+		b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+			t.PrivateChatID = 0
+			return true
+		})
+	}
+}
+
+// sendPrivateNotification delivers a ticket notification to the private chat.
+func (b *Bot) sendPrivateNotification(chatID int64, msg tgbotapi.MessageConfig) (tgbotapi.MessageConfig, error) {
+	msg.ChatID = chatID
+	msg.ReplyToMessageID = 0
+	_, err := sendMessage(b.api, msg, 0)
+	return msg, err
+}
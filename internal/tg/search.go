@@ -0,0 +1,240 @@
+package tg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"telegram-bot-jira/internal/jira"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CallbackSearch is the callback action of /search pagination buttons, "search|<page>".
+const CallbackSearch = "search"
+
+// searchTTL is how long the results of a /search can be paged.
+const searchTTL = time.Hour
+
+// searchMaxFetches bounds the Jira pages fetched to fill one page of results the account may browse.
+const searchMaxFetches = 10
+
+var (
+	// jqlOperatorRe tells JQL from text: comparison operators, "in (...)", "is empty" or "order by".
+	jqlOperatorRe = regexp.MustCompile(`(?i)(!=|!~|[=~<>]|\border\s+by\b|\b\w+\s+(not\s+)?in\s*\(|\b\w+\s+is\s+(not\s+)?(empty|null)\b)`)
+	jqlOrderByRe  = regexp.MustCompile(`(?i)\border\s+by\b`)
+	searchWordRe  = regexp.MustCompile(`[\p{L}\p{N}]+`)
+)
+
+// SearchJQL turns a search query into JQL limited to the project: an issue key, JQL, or words to search
+// for in the issue text. Empty if there is nothing to search or the JQL could escape the project filter.
+// Unless keys is nil, the search is also limited to these issues.
+func (p CtxParams) SearchJQL(query string, keys []string) string {
+	query = strings.TrimSpace(query)
+	project := fmt.Sprintf(`project = "%s"`, p.ProjectKey)
+	if keys != nil {
+		project += " AND " + jqlKeyFilter(keys)
+	}
+	if key := p.ProjectKeyRegexp.FindString(query); key != "" && len(key) == len(query) {
+		return fmt.Sprintf(`%s AND key = "%s"`, project, strings.ToUpper(key))
+	}
+	if jqlOperatorRe.MatchString(query) {
+		order := "ORDER BY updated DESC"
+		if loc := jqlOrderByRe.FindStringIndex(query); loc != nil {
+			query, order = strings.TrimSpace(query[:loc[0]]), strings.TrimSpace(query[loc[0]:])
+		}
+		if !jqlNested(query) || !jqlNested(order) {
+			return ""
+		}
+		if query == "" {
+			return project + " " + order
+		}
+		return fmt.Sprintf("%s AND (%s) %s", project, query, order)
+	}
+	words := searchWordRe.FindAllString(query, -1)
+	if len(words) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`%s AND text ~ "%s" ORDER BY updated DESC`, project, strings.Join(words, " "))
+}
+
+// jqlKeyFilter returns a JQL clause matching the issues; no issues match none.
+func jqlKeyFilter(keys []string) string {
+	if len(keys) == 0 {
+		return "key is EMPTY"
+	}
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = strconv.Quote(key)
+	}
+	return "key in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// SearchScope returns the limits of a search by the current user: in group chats only tickets of the chat
+// are found (keys), elsewhere, i.e. in private chats and inline mode, only issues the linked Jira account
+// may browse (account). ok is false if the user has no linked account there.
+func (c *Ctx) SearchScope() (keys []string, account string, ok bool) {
+	if chat := c.Tg.CurrentChat(); chat != nil && !chat.IsPrivate() {
+		keys = []string{}
+		for _, ticket := range c.TicketStore.ListByChatID(chat.ID) {
+			keys = append(keys, ticket.Key)
+		}
+		return keys, "", true
+	}
+	user := c.Tg.CurrentUser()
+	if user == nil {
+		return nil, "", false
+	}
+	account = c.JiraAccount(TelegramUserOf(user))
+	return nil, account, account != ""
+}
+
+// SearchCursor is where a page of search results starts: a Jira page token, "" for the first page,
+// and the number of issues of that Jira page shown before.
+type SearchCursor struct {
+	Token string
+	Skip  int
+}
+
+// SearchIssues returns up to limit issues of the JQL from the cursor and the cursor of the next page,
+// nil after the last one. With an account, issues it may not browse are skipped and further Jira pages
+// are fetched to fill the page, at most searchMaxFetches. A failed permission check fails the search.
+func (c *Ctx) SearchIssues(jql, account string, from SearchCursor, limit int) ([]jira.IssueStatus, *SearchCursor, error) {
+	var found []jira.IssueStatus
+	cursor := from
+	for fetches := 1; ; fetches++ {
+		// Skip counts issues of pages of this size, so every fetch of a search uses the same limit.
+		issues, next, err := c.Jira.SearchIssues(c.Std, jql, limit, cursor.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := cursor.Skip; i < len(issues); i++ {
+			if account != "" {
+				ok, err := c.Jira.HasIssuePermission(c.Std, issues[i].Key, account, "BROWSE_PROJECTS")
+				if err != nil {
+					return nil, nil, fmt.Errorf("permission check of %s: %w", issues[i].Key, err)
+				}
+				if !ok {
+					continue
+				}
+			}
+			found = append(found, issues[i])
+			if len(found) < limit {
+				continue
+			}
+			switch {
+			case i+1 < len(issues):
+				return found, &SearchCursor{Token: cursor.Token, Skip: i + 1}, nil
+			case next != "":
+				return found, &SearchCursor{Token: next}, nil
+			default:
+				return found, nil, nil
+			}
+		}
+		if next == "" {
+			return found, nil, nil
+		}
+		cursor = SearchCursor{Token: next}
+		if fetches == searchMaxFetches {
+			return found, &cursor, nil
+		}
+	}
+}
+
+// jqlNested reports whether parentheses outside quoted strings never close more than they open,
+// so the query stays inside the parentheses it is put in.
+func jqlNested(s string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0 && quote == 0
+}
+
+// SearchState is a /search whose results are paged with buttons.
+type SearchState struct {
+	Query   string
+	JQL     string
+	Account string         // Jira account whose permissions filter the results, "" in group chats
+	Pages   []SearchCursor // start of each page known so far
+	Updated time.Time
+}
+
+type searchKey struct {
+	chatID    int64
+	messageID int
+}
+
+// searches holds pageable /search results by the message showing them.
+type searches struct {
+	mu        sync.Mutex
+	byMessage map[searchKey]SearchState
+}
+
+func newSearches() *searches {
+	return &searches{byMessage: make(map[searchKey]SearchState)}
+}
+
+// Search returns the search shown in the message of the current chat.
+func (c *Ctx) Search(messageID int) (SearchState, bool) {
+	s := c.Params.searches
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := searchKey{chatID: c.Tg.CurrentChatId(), messageID: messageID}
+	state, ok := s.byMessage[key]
+	if ok && time.Since(state.Updated) > searchTTL {
+		delete(s.byMessage, key)
+		return SearchState{}, false
+	}
+	return state, ok
+}
+
+// SetSearch saves the search shown in the message of the current chat.
+func (c *Ctx) SetSearch(messageID int, state SearchState) {
+	s := c.Params.searches
+	state.Updated = time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, old := range s.byMessage {
+		if time.Since(old.Updated) > searchTTL {
+			delete(s.byMessage, key)
+		}
+	}
+	s.byMessage[searchKey{chatID: c.Tg.CurrentChatId(), messageID: messageID}] = state
+}
+
+// AnswerInlineQuery answers the current inline query; results depend on the user and are cached briefly.
+func (bot *BotTgAction) AnswerInlineQuery(results []any) error {
+	if results == nil {
+		results = []any{}
+	}
+	_, err := bot.tgApi.Request(tgbotapi.InlineConfig{
+		InlineQueryID: bot.ctx.Upd.InlineQuery.ID,
+		Results:       results,
+		CacheTime:     30,
+		IsPersonal:    true,
+	})
+	return err
+}
@@ -0,0 +1,136 @@
+package tg
+
+import (
+	"time"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/sla"
+	"telegram-bot-jira/internal/text"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func ticketSLATimes(ticket *CreatedTicket) sla.Times {
+	return sla.Times{CreatedAt: ticket.CreatedAt, FirstResponseAt: ticket.FirstResponseAt, ResolvedAt: ticket.ResolvedAt}
+}
+
+// ticketSLA evaluates the SLA of a stored ticket with the given Jira priority; nil if no SLA applies.
+func (b *Bot) ticketSLA(ticket *CreatedTicket, priority string) *sla.Status {
+	return evaluateSLA(b.sla, ticket, priority)
+}
+
+// isAgentComment reports whether a comment was written by a person in Jira rather than by the bot account.
+func (b *Bot) isAgentComment(comment *jira.Comment) bool {
+	if b.selfAccountID != "" {
+		return comment.Author.AccountID != b.selfAccountID
+	}
+	return comment.Author.Email == "" || comment.Author.Email != b.cfg.JiraEmail
+}
+
+// trackFirstResponse stores the time of the earliest agent comment on the ticket.
+func (b *Bot) trackFirstResponse(ticket *CreatedTicket, comments []jira.Comment) {
+	if !ticket.FirstResponseAt.IsZero() {
+		return
+	}
+	var first time.Time
+	for i := range comments {
+		created := comments[i].Created.Time
+		if created.Before(ticket.CreatedAt) || !b.isAgentComment(&comments[i]) {
+			continue
+		}
+		if first.IsZero() || created.Before(first) {
+			first = created
+		}
+	}
+	if first.IsZero() {
+		return
+	}
+	ticket.FirstResponseAt = first
+	b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+		t.FirstResponseAt = first
+		return true
+	})
+}
+
+// trackResolution keeps CreatedAt and ResolvedAt in sync with the issue: resolved when it
+// reaches a "ready" status, unresolved again when reopened.
+func (b *Bot) trackResolution(ticket *CreatedTicket, issue *jira.IssueStatus) {
+	ready := text.IsReadyStatus(issue.Status)
+	b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+		changed := false
+		if t.CreatedAt.IsZero() && !issue.Created.IsZero() {
+			t.CreatedAt = issue.Created
+			changed = true
+		}
+		if ready && t.ResolvedAt.IsZero() {
+			t.ResolvedAt = time.Now()
+			changed = true
+		} else if !ready && !t.ResolvedAt.IsZero() {
+			t.ResolvedAt = time.Time{}
+			t.SLAFlags &^= sla.ResolutionWarned | sla.ResolutionBreached
+			changed = true
+		}
+		*ticket = *t
+		return changed
+	})
+}
+
+// checkSLA sends a warning before an SLA deadline and an alert on breach, each once per ticket.
+func (b *Bot) checkSLA(ticket *CreatedTicket, issue *jira.IssueStatus) {
+	policy := b.sla.For(ticket.ChatID, issue.Priority)
+	if policy == nil || ticket.Tracked {
+		return
+	}
+	status := policy.Evaluate(ticketSLATimes(ticket), time.Now())
+	checks := []struct {
+		target               string
+		due                  time.Time
+		warning, breached    bool
+		warnFlag, breachFlag uint8
+	}{
+		{"first_response", status.FirstResponseDue, status.FirstResponseWarning, status.FirstResponseBreached && ticket.FirstResponseAt.IsZero(),
+			sla.FirstResponseWarned, sla.FirstResponseBreached},
+		{"resolution", status.ResolutionDue, status.ResolutionWarning, status.ResolutionBreached && ticket.ResolvedAt.IsZero(),
+			sla.ResolutionWarned, sla.ResolutionBreached},
+	}
+	for _, c := range checks {
+		var flag uint8
+		switch {
+		case c.breached && ticket.SLAFlags&c.breachFlag == 0:
+			flag = c.breachFlag | c.warnFlag
+		case c.warning && ticket.SLAFlags&(c.warnFlag|c.breachFlag) == 0:
+			flag = c.warnFlag
+		default:
+			continue
+		}
+		b.sendSLAAlert(ticket, issue, text.SLAAlertData{
+			Key:      ticket.Key,
+			Summary:  issue.Summary,
+			URL:      b.jira.BrowseURL(ticket.Key),
+			Target:   c.target,
+			Due:      c.due,
+			Breached: c.breached,
+		})
+		ticket.SLAFlags |= flag
+		b.ticketStore.Update(ticket.Key, func(t *CreatedTicket) bool {
+			t.SLAFlags |= flag
+			return true
+		})
+	}
+}
+
+func (b *Bot) sendSLAAlert(ticket *CreatedTicket, issue *jira.IssueStatus, data text.SLAAlertData) {
+	b.log.Info("SLA alert", "key", ticket.Key, "target", data.Target, "breached", data.Breached)
+	msg := tgbotapi.NewMessage(ticket.ChatID, text.TextSLAAlertHTML(b.chatLang(ticket.ChatID), data))
+	msg.ParseMode = tgbotapi.ModeHTML
+	if err := b.sendTicketNotification(ticket, msg); err != nil {
+		b.log.Error("Failed to send SLA alert", "key", ticket.Key, "err", err)
+	}
+	if b.cfg.ErrorChatID != 0 {
+		msg := tgbotapi.NewMessage(int64(b.cfg.ErrorChatID), text.TextSLAAlertHTML(text.DefaultLang(), data))
+		msg.ParseMode = tgbotapi.ModeHTML
+		if _, err := b.api.Send(msg); err != nil {
+			b.log.Error("Failed to send SLA alert to error chat", "key", ticket.Key, "err", err)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package tg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+	"telegram-bot-jira/internal/store"
+)
+
+const (
+	storageProperties = "properties"
+	storageAggregate  = "aggregate"
+
+	// ticketProperty is the entity property on each tracked issue holding its bot metadata.
+	ticketProperty        = "telegram-bot"
+	ticketPropertyVersion = 2
+
+	// ticketIndexProperty is the project entity property listing the keys of tracked issues. Jira
+	// only indexes entity properties declared by an app, so they cannot be found with JQL.
+	ticketIndexProperty        = "telegram-bot-tickets"
+	ticketIndexPropertyVersion = 2
+	// ticketIndexPartBytes bounds the encoded keys of one index property, below the 32 KB limit Jira
+	// puts on an entity property value.
+	ticketIndexPartBytes = 30 * 1024
+)
+
+// ticketIndexValue is the versioned JSON stored in ticketIndexProperty. Keys that do not fit into it
+// are split over Parts more properties, see ticketIndexPartProperty, holding only Keys.
+type ticketIndexValue struct {
+	Version int      `json:"version"`
+	Keys    []string `json:"keys"`
+	Parts   int      `json:"parts,omitempty"`
+}
+
+// ticketIndexPartProperty returns the property of the i-th additional part of the index, from 1.
+func ticketIndexPartProperty(i int) string {
+	return fmt.Sprintf("%s-%d", ticketIndexProperty, i)
+}
+
+// ticketIndex is the last key list read from or written to ticketIndexProperty. It is written only
+// after the tickets were loaded, so a failed start does not wipe it.
+type ticketIndex struct {
+	loaded bool
+	keys   string
+	parts  int // additional parts written
+}
+
+// ticketPropertyValue is the versioned JSON stored in ticketProperty.
+type ticketPropertyValue struct {
+	Version int `json:"version"`
+	CreatedTicket
+}
+
+// ticketV1 is a ticket as stored by version 1, which identified the creator by username only.
+type ticketV1 struct {
+	CreatedTicket
+	CreatorUsername string `json:"creator_username"`
+	CreatorID       int64  `json:"creator_id"`
+}
+
+// upgrade converts a version 1 ticket. Creator IDs and names unknown at that time are filled in
+// when the creator next writes in Telegram, see TicketStore.UpdateUser.
+func (t ticketV1) upgrade() CreatedTicket {
+	ticket := t.CreatedTicket
+	if ticket.Creator.IsZero() {
+		ticket.Creator = store.TelegramUser{ID: t.CreatorID, Username: strings.TrimPrefix(t.CreatorUsername, "@")}
+	}
+	return ticket
+}
+
+// loadTickets fills the ticket store from the configured storage on startup.
+func (b *Bot) loadTickets(ctx context.Context) error {
+	switch b.cfg.TicketStorage {
+	case storageAggregate:
+		if b.cfg.AggregateIssueKey == "" {
+			return nil
+		}
+		return b.syncAggregateFromJira(ctx)
+	default:
+		err := b.loadTicketProperties(ctx)
+		b.health.storageResult(err)
+		return err
+	}
+}
+
+// loadTicketProperties rebuilds the ticket store from the properties of the indexed issues. Without
+// an index yet, tickets are found with TICKET_PROPERTY_JQL or migrated from the aggregate issue.
+func (b *Bot) loadTicketProperties(ctx context.Context) error {
+	raw, err := b.jira.GetProjectProperty(ctx, ticketIndexProperty)
+	if err == nil {
+		var index ticketIndexValue
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return fmt.Errorf("ticket index: %w", err)
+		}
+		if index.Version > ticketIndexPropertyVersion {
+			return fmt.Errorf("ticket index version %d is newer than supported %d", index.Version, ticketIndexPropertyVersion)
+		}
+		keys := index.Keys
+		for i := 1; i <= index.Parts; i++ {
+			raw, err := b.jira.GetProjectProperty(ctx, ticketIndexPartProperty(i))
+			if err != nil {
+				return fmt.Errorf("ticket index part %d: %w", i, err)
+			}
+			var part ticketIndexValue
+			if err := json.Unmarshal(raw, &part); err != nil {
+				return fmt.Errorf("ticket index part %d: %w", i, err)
+			}
+			keys = append(keys, part.Keys...)
+		}
+		if err := b.loadIndexedTickets(ctx, keys); err != nil {
+			return err
+		}
+		b.ticketIndex = ticketIndex{loaded: true, keys: strings.Join(keys, ","), parts: index.Parts}
+		return nil
+	}
+	if !errors.Is(err, jira.ErrNotFound) {
+		return err
+	}
+
+	switch {
+	case b.cfg.TicketPropertyJQL != "":
+		if err := b.searchTicketProperties(ctx, b.cfg.TicketPropertyJQL); err != nil {
+			return err
+		}
+	case b.cfg.AggregateIssueKey != "":
+		// First start after switching from the aggregate issue: copy its tickets into properties.
+		if err := b.syncAggregateFromJira(ctx); err != nil {
+			return err
+		}
+		var keys []string
+		for _, ticket := range b.ticketStore.ListAll() {
+			keys = append(keys, ticket.Key)
+		}
+		b.ticketStore.MarkDirty(keys...)
+		b.log.Info("Migrating tickets from aggregate issue to issue properties", slog.Int("size", len(keys)))
+	}
+	b.ticketIndex.loaded = true
+	return nil
+}
+
+// loadIndexedTickets reads the ticket property of each indexed issue. Issues deleted since, or without
+// the property, are dropped from the index on the next save.
+func (b *Bot) loadIndexedTickets(ctx context.Context, keys []string) error {
+	var tickets []CreatedTicket
+	var upgraded []string
+	for _, key := range keys {
+		raw, err := b.jira.GetIssueProperty(ctx, key, ticketProperty)
+		if errors.Is(err, jira.ErrNotFound) {
+			b.log.Warn("Skip indexed ticket without property", slog.String("key", key))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("ticket %s: %w", key, err)
+		}
+		ticket, old, ok := b.decodeTicketProperty(key, raw)
+		if !ok {
+			continue
+		}
+		if old {
+			upgraded = append(upgraded, key)
+		}
+		tickets = append(tickets, ticket)
+	}
+	b.ticketStore.Init(tickets)
+	// rewrite properties of older versions in the current format
+	b.ticketStore.MarkDirty(upgraded...)
+	b.log.Info("Load tickets from issue properties", slog.Int("size", len(tickets)), slog.Int("upgraded", len(upgraded)))
+	return nil
+}
+
+// saveTickets persists changes of the ticket store made since the previous call.
+func (b *Bot) saveTickets(ctx context.Context) {
+	if b.cfg.TicketStorage == storageAggregate {
+		b.ticketStore.DirtyKeysAndReset()
+		if b.ticketStore.DirtyAndReset() {
+			b.updateAggregateToJira(ctx)
+		}
+		return
+	}
+	b.ticketStore.DirtyAndReset()
+	for _, key := range b.ticketStore.DirtyKeysAndReset() {
+		var err error
+		if ticket := b.ticketStore.Get(key); ticket != nil {
+			err = b.jira.SetIssueProperty(ctx, key, ticketProperty, ticketPropertyValue{Version: ticketPropertyVersion, CreatedTicket: ticket.UTC()})
+		} else {
+			err = b.jira.DeleteIssueProperty(ctx, key, ticketProperty)
+		}
+		b.health.storageResult(err)
+		if err != nil {
+			b.log.Error("Failed to save ticket property", slog.String("key", key), slog.Any("err", err))
+			b.ticketStore.MarkDirty(key) // retry on the next poll cycle
+		}
+	}
+	b.saveTicketIndex(ctx)
+}
+
+// searchTicketProperties rebuilds the ticket store from issues found by the JQL, used once to build
+// the index of installations that do not have one yet.
+func (b *Bot) searchTicketProperties(ctx context.Context, jql string) error {
+	var tickets []CreatedTicket
+	var upgraded []string
+	pageToken := ""
+	for {
+		values, next, err := b.jira.SearchIssueProperties(ctx, jql, ticketProperty, pageToken)
+		if err != nil {
+			return err
+		}
+		for key, raw := range values {
+			ticket, old, ok := b.decodeTicketProperty(key, raw)
+			if !ok {
+				continue
+			}
+			if old {
+				upgraded = append(upgraded, key)
+			}
+			tickets = append(tickets, ticket)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	b.ticketStore.Init(tickets)
+	b.ticketStore.MarkDirty(upgraded...)
+	b.log.Info("Load tickets from issue properties found by JQL", slog.Int("size", len(tickets)), slog.Int("upgraded", len(upgraded)))
+	return nil
+}
+
+// decodeTicketProperty parses a ticket property; old reports a version to rewrite, !ok one to skip.
+func (b *Bot) decodeTicketProperty(key string, raw json.RawMessage) (ticket CreatedTicket, old, ok bool) {
+	var value ticketPropertyValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		b.log.Warn("Skip invalid ticket property", slog.String("key", key), slog.Any("err", err))
+		return CreatedTicket{}, false, false
+	}
+	if value.Version > ticketPropertyVersion {
+		b.log.Warn("Skip ticket property of newer version", slog.String("key", key), slog.Int("version", value.Version))
+		return CreatedTicket{}, false, false
+	}
+	if value.Version < ticketPropertyVersion {
+		var legacy ticketV1
+		if err := json.Unmarshal(raw, &legacy); err == nil {
+			value.CreatedTicket = legacy.upgrade()
+		}
+		old = true
+	}
+	value.Key = key
+	return value.CreatedTicket, old, true
+}
+
+// saveTicketIndex writes the keys of the ticket store into the index if they changed. The additional
+// parts are written before the main property that counts them; parts no longer needed are removed after.
+func (b *Bot) saveTicketIndex(ctx context.Context) {
+	if !b.ticketIndex.loaded {
+		return
+	}
+	var keys []string
+	for _, ticket := range b.ticketStore.ListAll() {
+		keys = append(keys, ticket.Key)
+	}
+	sort.Strings(keys)
+	joined := strings.Join(keys, ",")
+	if joined == b.ticketIndex.keys {
+		return
+	}
+	parts := splitTicketIndex(keys)
+	var err error
+	for i := len(parts) - 1; i >= 1 && err == nil; i-- {
+		err = b.jira.SetProjectProperty(ctx, ticketIndexPartProperty(i), ticketIndexValue{Version: ticketIndexPropertyVersion, Keys: parts[i]})
+	}
+	if err == nil {
+		err = b.jira.SetProjectProperty(ctx, ticketIndexProperty, ticketIndexValue{Version: ticketIndexPropertyVersion, Keys: parts[0], Parts: len(parts) - 1})
+	}
+	b.health.storageResult(err)
+	if err != nil {
+		b.log.Error("Failed to save ticket index", slog.Int("size", len(keys)), slog.Int("parts", len(parts)), slog.Any("err", err))
+		return // retried on the next poll cycle
+	}
+	for i := len(parts); i <= b.ticketIndex.parts; i++ {
+		if err := b.jira.DeleteProjectProperty(ctx, ticketIndexPartProperty(i)); err != nil {
+			b.log.Warn("Failed to delete unused ticket index part", slog.Int("part", i), slog.Any("err", err))
+		}
+	}
+	b.ticketIndex.keys = joined
+	b.ticketIndex.parts = len(parts) - 1
+}
+
+// splitTicketIndex splits the keys into parts of at most ticketIndexPartBytes encoded; there is always
+// at least one part.
+func splitTicketIndex(keys []string) [][]string {
+	parts := [][]string{nil}
+	size := 0
+	for _, key := range keys {
+		n := len(strconv.Quote(key)) + 1
+		if size+n > ticketIndexPartBytes && len(parts[len(parts)-1]) > 0 {
+			parts = append(parts, nil)
+			size = 0
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], key)
+		size += n
+	}
+	return parts
+}
@@ -1,6 +1,12 @@
 package tg
 
-import "telegram-bot-jira/internal/store"
+import (
+	"strings"
+
+	"telegram-bot-jira/internal/store"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
 
 type TicketStore = store.TicketStore
 type CreatedTicket = store.CreatedTicket
@@ -8,3 +14,13 @@ type CreatedTicket = store.CreatedTicket
 func NewTicketStore() *TicketStore {
 	return store.NewTicketStore()
 }
+
+type TelegramUser = store.TelegramUser
+
+// TelegramUserOf returns the identity of a Telegram user as stored in tickets.
+func TelegramUserOf(user *tgbotapi.User) TelegramUser {
+	if user == nil {
+		return TelegramUser{}
+	}
+	return TelegramUser{ID: user.ID, Username: user.UserName, Name: strings.TrimSpace(user.FirstName + " " + user.LastName)}
+}
@@ -0,0 +1,31 @@
+package tg
+
+import (
+	"strings"
+	"time"
+
+	"telegram-bot-jira/internal/jira"
+)
+
+// TrackTicket attaches an existing issue to the current chat and topic, so its new comments and
+// status changes are forwarded like those of tickets created by the bot. The Jira reporter becomes
+// the ticket creator if their account is linked.
+func (c *Ctx) TrackTicket(issue *jira.IssueStatus) {
+	var creator TelegramUser
+	if issue.ReporterAccountID != "" {
+		creator, _ = c.Params.identities.user(issue.ReporterAccountID)
+	}
+	c.TicketStore.Add(c.Tg.CurrentChatId(), issue.Key, issue.Status, issue.Summary, creator)
+	c.TicketStore.Update(issue.Key, func(t *CreatedTicket) bool {
+		t.Summary = issue.Summary
+		t.Assignee, t.AssigneeSeen = strings.TrimSpace(issue.Assignee), true
+		t.ThreadID = c.ThreadID
+		if c.Upd.Message != nil {
+			t.SourceMessageID = c.Upd.Message.MessageID
+		}
+		// Earlier comments are history, only new ones are forwarded.
+		t.LastCommentAt = time.Now()
+		t.Tracked = true
+		return true
+	})
+}
@@ -0,0 +1,148 @@
+package tg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"telegram-bot-jira/internal/jira"
+)
+
+// jiraUserMap links Telegram users to Jira accounts (JIRA_USER_MAP), so that their
+// Telegram subscriptions follow the issue watchers in Jira and back.
+type jiraUserMap struct {
+	byID       map[int64]string
+	byUsername map[string]string // lower-case, without @
+}
+
+// parseJiraUserMap parses "<telegram id or @username>=<account id>,...".
+func parseJiraUserMap(s string) (jiraUserMap, error) {
+	m := jiraUserMap{byID: make(map[int64]string), byUsername: make(map[string]string)}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		user, account, ok := strings.Cut(item, "=")
+		user, account = strings.TrimSpace(user), strings.TrimSpace(account)
+		if !ok || user == "" || account == "" {
+			return m, fmt.Errorf("invalid entry %q, want <telegram id or @username>=<account id>", item)
+		}
+		if name, ok := strings.CutPrefix(user, "@"); ok {
+			m.byUsername[strings.ToLower(name)] = account
+			continue
+		}
+		id, err := strconv.ParseInt(user, 10, 64)
+		if err != nil {
+			return m, fmt.Errorf("invalid telegram user id %q", user)
+		}
+		m.byID[id] = account
+	}
+	return m, nil
+}
+
+func (m jiraUserMap) empty() bool {
+	return len(m.byID) == 0 && len(m.byUsername) == 0
+}
+
+// account returns the Jira account of the Telegram user, "" if the user is not mapped.
+func (m jiraUserMap) account(user TelegramUser) string {
+	if account, ok := m.byID[user.ID]; ok && user.ID != 0 {
+		return account
+	}
+	if user.Username == "" {
+		return ""
+	}
+	return m.byUsername[strings.ToLower(user.Username)]
+}
+
+// user returns the Telegram user mapped to the Jira account.
+func (m jiraUserMap) user(account string) (TelegramUser, bool) {
+	for id, a := range m.byID {
+		if a == account {
+			return TelegramUser{ID: id}, true
+		}
+	}
+	for username, a := range m.byUsername {
+		if a == account {
+			return TelegramUser{Username: username}, true
+		}
+	}
+	return TelegramUser{}, false
+}
+
+// WatchTicket subscribes the user to the ticket or unsubscribes; for users mapped to Jira accounts
+// the Jira watchers are updated first. Reports whether anything changed.
+func (c *Ctx) WatchTicket(key string, user TelegramUser, watch bool) (bool, error) {
+	ticket := c.TicketStore.Get(key)
+	if ticket == nil || ticket.WatchedBy(user.ID, user.Username) == watch {
+		return false, nil
+	}
+	if account := c.JiraAccount(user); account != "" {
+		var err error
+		if watch {
+			err = c.Jira.AddWatcher(c.Std, key, account)
+		} else {
+			err = c.Jira.RemoveWatcher(c.Std, key, account)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if watch {
+		return c.TicketStore.Watch(key, user), nil
+	}
+	return c.TicketStore.Unwatch(key, user), nil
+}
+
+// syncJiraWatchers makes Telegram subscriptions of mapped users follow the issue watchers in Jira.
+// Watchers are fetched on the first poll and then only when the issue was updated.
+func (b *Bot) syncJiraWatchers(ctx context.Context, ticket *CreatedTicket, issue *jira.IssueStatus) {
+	if b.identities.empty() {
+		return
+	}
+	if synced, ok := b.watchersSynced[ticket.Key]; ok && synced.Equal(issue.Updated) {
+		return
+	}
+	accounts, err := b.jira.GetWatchers(ctx, ticket.Key)
+	if err != nil {
+		b.log.Warn("Failed to get issue watchers", "key", ticket.Key, "error", err)
+		return
+	}
+	b.watchersSynced[ticket.Key] = issue.Updated
+	inJira := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		inJira[account] = true
+		user, ok := b.identities.user(account)
+		if ok && !ticket.CreatedBy(user.ID, user.Username) && b.ticketStore.Watch(ticket.Key, user) {
+			b.log.Info("Watcher added from Jira", "key", ticket.Key, "account", account)
+		}
+	}
+	for _, watcher := range ticket.Watchers {
+		if account := b.identities.account(watcher); account != "" && !inJira[account] && b.ticketStore.Unwatch(ticket.Key, watcher) {
+			b.log.Info("Watcher removed in Jira", "key", ticket.Key, "account", account)
+		}
+	}
+	if t := b.ticketStore.Get(ticket.Key); t != nil {
+		*ticket = *t
+	}
+}
+
+// pruneWatchersSynced forgets sync times of tickets that are no longer tracked.
+func (b *Bot) pruneWatchersSynced() {
+	for key := range b.watchersSynced {
+		if b.ticketStore.Get(key) == nil {
+			delete(b.watchersSynced, key)
+		}
+	}
+}
+
+// recipients returns users to mention in a ticket notification: the creator, if withCreator, and the watchers.
+func recipients(ticket *CreatedTicket, withCreator bool) []TelegramUser {
+	var users []TelegramUser
+	if withCreator {
+		users = append(users, ticket.Creator)
+	}
+	return append(users, ticket.Watchers...)
+}
@@ -0,0 +1,230 @@
+package tg
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"telegram-bot-jira/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Steps of the creation wizard; the confirmation always comes last. The duplicate step offers similar
+// open tickets before one is created.
+const (
+	WizardStepSummary   = "summary"
+	WizardStepType      = "type"
+	WizardStepPriority  = "priority"
+	WizardStepComponent = "component"
+	WizardStepConfirm   = "confirm"
+	WizardStepDuplicate = "duplicate"
+)
+
+// CallbackWizard is the callback action of wizard buttons, "wizard|<user ID>|<command>|<option>".
+const CallbackWizard = "wizard"
+
+// wizardTTL is how long an idle wizard is kept.
+const wizardTTL = 30 * time.Minute
+
+var wizardSteps = []string{WizardStepSummary, WizardStepType, WizardStepPriority, WizardStepComponent}
+
+// checkWizardSteps validates step names of CREATE_WIZARD_STEPS and the chat settings.
+func checkWizardSteps(cfg config.Config) error {
+	check := func(steps []string) error {
+		for _, step := range steps {
+			if !slices.Contains(wizardSteps, step) {
+				return fmt.Errorf("unknown step %q, want one of %v", step, wizardSteps)
+			}
+		}
+		return nil
+	}
+	if err := check(cfg.WizardSteps); err != nil {
+		return err
+	}
+	for chatID, chat := range cfg.Chats {
+		if err := check(chat.Wizard.Steps); err != nil {
+			return fmt.Errorf("chat %d: %w", chatID, err)
+		}
+	}
+	return nil
+}
+
+// WizardState is the creation wizard of one user in one chat.
+type WizardState struct {
+	Steps      []string          // steps with something to ask, then WizardStepConfirm
+	Step       int               // index in Steps
+	Summary    string            // answer of the summary step
+	Choices    map[string]string // chosen option by step
+	Creator    TelegramUser
+	Args       map[string]string  // key=value arguments of /create_issue
+	History    []tgbotapi.Message // chat history when the wizard started
+	ThreadID   int
+	MessageID  int         // the /create_issue message
+	PromptID   int         // bot message of the current step
+	Duplicates []Duplicate // similar tickets of the duplicate step
+	Updated    time.Time
+}
+
+// Current returns the current step, empty if Step is out of range.
+func (w *WizardState) Current() string {
+	if w.Step < 0 || w.Step >= len(w.Steps) {
+		return ""
+	}
+	return w.Steps[w.Step]
+}
+
+type wizardKey struct {
+	chatID int64
+	userID int64
+}
+
+// wizards holds wizards in progress; each user has own wizard per chat.
+type wizards struct {
+	mu    sync.Mutex
+	byKey map[wizardKey]WizardState
+}
+
+func newWizards() *wizards {
+	return &wizards{byKey: make(map[wizardKey]WizardState)}
+}
+
+// Wizard returns a copy of the wizard of the user in the current chat; its choices may be changed
+// and saved with SetWizard.
+func (c *Ctx) Wizard(userID int64) (WizardState, bool) {
+	w := c.Params.wizards
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := wizardKey{chatID: c.Tg.CurrentChatId(), userID: userID}
+	state, ok := w.byKey[key]
+	if ok && time.Since(state.Updated) > wizardTTL {
+		delete(w.byKey, key)
+		return WizardState{}, false
+	}
+	state.Choices = maps.Clone(state.Choices)
+	return state, ok
+}
+
+// SetWizard saves the wizard of the user in the current chat.
+func (c *Ctx) SetWizard(userID int64, state WizardState) {
+	w := c.Params.wizards
+	state.Updated = time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, s := range w.byKey {
+		if time.Since(s.Updated) > wizardTTL {
+			delete(w.byKey, key)
+		}
+	}
+	w.byKey[wizardKey{chatID: c.Tg.CurrentChatId(), userID: userID}] = state
+}
+
+// EndWizard drops the wizard of the user in the current chat.
+func (c *Ctx) EndWizard(userID int64) {
+	w := c.Params.wizards
+	w.mu.Lock()
+	delete(w.byKey, wizardKey{chatID: c.Tg.CurrentChatId(), userID: userID})
+	w.mu.Unlock()
+}
+
+// TakeWizard returns and drops the wizard of the user in the current chat if its current prompt is
+// promptID, so that the final action of a wizard runs once even if the button is pressed twice.
+func (c *Ctx) TakeWizard(userID int64, promptID int) (WizardState, bool) {
+	w := c.Params.wizards
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := wizardKey{chatID: c.Tg.CurrentChatId(), userID: userID}
+	state, ok := w.byKey[key]
+	if !ok || state.PromptID != promptID {
+		return WizardState{}, false
+	}
+	delete(w.byKey, key)
+	if time.Since(state.Updated) > wizardTTL {
+		return WizardState{}, false
+	}
+	return state, true
+}
+
+// IsWizardReply reports whether the message answers the current step prompt of the sender's wizard.
+func (c *Ctx) IsWizardReply(message *tgbotapi.Message) bool {
+	if message.From == nil || message.ReplyToMessage == nil {
+		return false
+	}
+	state, ok := c.Wizard(message.From.ID)
+	return ok && state.PromptID == message.ReplyToMessage.MessageID
+}
+
+// WizardSteps returns the wizard steps of the chat with something to ask: choice steps
+// without options are skipped. Empty means the chat has no wizard.
+func (p CtxParams) WizardSteps(chatID int64) []string {
+	settings := p.Chat(chatID).Wizard
+	steps := settings.Steps
+	if steps == nil {
+		steps = p.wizardSteps
+	}
+	var out []string
+	for _, step := range steps {
+		if step == WizardStepSummary || len(p.WizardOptions(chatID, step)) > 0 {
+			out = append(out, step)
+		}
+	}
+	return out
+}
+
+// WizardOptions returns the options of a choice step in the chat.
+func (p CtxParams) WizardOptions(chatID int64, step string) []string {
+	settings := p.Chat(chatID).Wizard
+	switch step {
+	case WizardStepType:
+		return settings.Types
+	case WizardStepPriority:
+		return settings.Priorities
+	case WizardStepComponent:
+		return settings.Components
+	}
+	return nil
+}
+
+// WizardFields converts the choices into Jira fields.
+func WizardFields(choices map[string]string) map[string]any {
+	fields := make(map[string]any)
+	if v := choices[WizardStepType]; v != "" {
+		fields["issuetype"] = map[string]any{"name": v}
+	}
+	if v := choices[WizardStepPriority]; v != "" {
+		fields["priority"] = map[string]any{"name": v}
+	}
+	if v := choices[WizardStepComponent]; v != "" {
+		fields["components"] = []any{map[string]any{"name": v}}
+	}
+	return fields
+}
+
+// SendForceReply sends a prompt in reply to the message that the user answers by replying to it.
+func (bot *BotTgAction) SendForceReply(text string, replyTo int, placeholder string) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewMessage(bot.CurrentChatId(), text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.ReplyToMessageID = replyTo
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true, InputFieldPlaceholder: placeholder}
+	return sendMessage(bot.tgApi, msg, bot.ctx.ThreadID)
+}
+
+// EditMessageHTML replaces the text and buttons of a bot message in the current chat.
+func (bot *BotTgAction) EditMessageHTML(messageID int, text string, buttons ...[]tgbotapi.InlineKeyboardButton) error {
+	edit := tgbotapi.NewEditMessageText(bot.CurrentChatId(), messageID, text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	if len(buttons) > 0 {
+		markup := tgbotapi.NewInlineKeyboardMarkup(buttons...)
+		edit.ReplyMarkup = &markup
+	}
+	_, err := bot.tgApi.Send(edit)
+	return err
+}
+
+// DeleteMessage deletes a message in the current chat.
+func (bot *BotTgAction) DeleteMessage(messageID int) error {
+	_, err := bot.tgApi.Request(tgbotapi.NewDeleteMessage(bot.CurrentChatId(), messageID))
+	return err
+}